@@ -17,9 +17,10 @@ type MessageCountMap map[string]int
 // Aggregate represents an object that contains multiple errors, but does not
 // necessarily have singular semantic meaning.
 // The aggregate can be used with `errors.Is()` to check for the occurrence of
-// a specific error type.
-// Errors.As() is not supported, because the caller presumably cares about a
-// specific error of potentially multiple that match the given type.
+// a specific error type, and with `errors.As()` to find the first error, in
+// depth-first order, that matches the given type.
+// Aggregates created by this package also implement `Unwrap() []error`, so
+// they are part of the error tree of the standard library.
 type Aggregate interface {
 	error
 	Errors() []error
@@ -88,20 +89,29 @@ func (agg aggregate) Is(target error) bool {
 	})
 }
 
+// As finds the first error of the aggregate, in depth-first order, that
+// matches target, and if so, sets target to that error value and returns true.
+func (agg aggregate) As(target interface{}) bool {
+	return agg.visit(func(err error) bool {
+		return errors.As(err, target)
+	})
+}
+
+// visit calls f for every error of the aggregate, descending into nested
+// aggregates and multi-errors, until f returns true.
 func (agg aggregate) visit(f func(err error) bool) bool {
-	for _, err := range agg {
-		switch err := err.(type) {
-		case aggregate:
-			if match := err.visit(f); match {
+	return visitErrors(agg, f)
+}
+
+func visitErrors(list []error, f func(err error) bool) bool {
+	for _, err := range list {
+		if nested, ok := multiErrors(err); ok {
+			if match := visitErrors(nested, f); match {
 				return match
 			}
-		case Aggregate:
-			for _, nestedErr := range err.Errors() {
-				if match := f(nestedErr); match {
-					return match
-				}
-			}
-		default:
+			continue
+		}
+		if err != nil {
 			if match := f(err); match {
 				return match
 			}
@@ -111,18 +121,40 @@ func (agg aggregate) visit(f func(err error) bool) bool {
 	return false
 }
 
+// multiErrors returns the errors of an Aggregate or of a multi-error
+// implementing `Unwrap() []error`, such as the result of errors.Join.
+func multiErrors(err error) ([]error, bool) {
+	switch err := err.(type) {
+	case Aggregate:
+		return err.Errors(), true
+	case interface{ Unwrap() []error }:
+		return err.Unwrap(), true
+	}
+
+	return nil, false
+}
+
 // Errors is part of the Aggregate interface.
 func (agg aggregate) Errors() []error {
 	return []error(agg)
 }
 
+// Unwrap returns the errors of the aggregate, making it a node of the
+// multi-error tree of the standard library.
+func (agg aggregate) Unwrap() []error {
+	errs := make([]error, len(agg))
+	copy(errs, agg)
+
+	return errs
+}
+
 // Matcher is used to match errors.  Returns true if the error matches.
 type Matcher func(error) bool
 
 // FilterOut removes all errors that match any of the matchers from the input
 // error.  If the input is a singular error, only that error is tested.  If the
-// input implements the Aggregate interface, the list of errors will be
-// processed recursively.
+// input implements the Aggregate interface or is a multi-error implementing
+// `Unwrap() []error`, the list of errors will be processed recursively.
 //
 // This can be used, for example, to remove known-OK errors (such as io.EOF or
 // os.PathNotFound) from a list of errors.
@@ -130,8 +162,8 @@ func FilterOut(err error, fns ...Matcher) error {
 	if err == nil {
 		return nil
 	}
-	if agg, ok := err.(Aggregate); ok {
-		return NewAggregate(filterErrors(agg.Errors(), fns...))
+	if errs, ok := multiErrors(err); ok {
+		return NewAggregate(filterErrors(errs, fns...))
 	}
 	if !matchesError(err, fns...) {
 		return err
@@ -164,25 +196,18 @@ func filterErrors(list []error, fns ...Matcher) []error {
 	return result
 }
 
-// Flatten takes an Aggregate, which may hold other Aggregates in arbitrary
-// nesting, and flattens them all into a single Aggregate, recursively.
+// Flatten takes an Aggregate, which may hold other Aggregates or multi-errors
+// implementing `Unwrap() []error` in arbitrary nesting, and flattens them all
+// into a single Aggregate, recursively.
 func Flatten(agg Aggregate) Aggregate {
-	result := []error{}
 	if agg == nil {
 		return nil
 	}
-	for _, err := range agg.Errors() {
-		if a, ok := err.(Aggregate); ok {
-			r := Flatten(a)
-			if r != nil {
-				result = append(result, r.Errors()...)
-			}
-		} else {
-			if err != nil {
-				result = append(result, err)
-			}
-		}
-	}
+	result := []error{}
+	visitErrors(agg.Errors(), func(err error) bool {
+		result = append(result, err)
+		return false
+	})
 	return NewAggregate(result)
 }
 
@@ -202,13 +227,13 @@ func CreateAggregateFromMessageCountMap(m MessageCountMap) Aggregate {
 	return NewAggregate(result)
 }
 
-// Reduce will return err or, if err is an Aggregate and only has one item,
-// the first item in the aggregate.
+// Reduce will return err or, if err is an Aggregate or a multi-error and only
+// has one item, the first item in the aggregate.
 func Reduce(err error) error {
-	if agg, ok := err.(Aggregate); ok && err != nil {
-		switch len(agg.Errors()) {
+	if errs, ok := multiErrors(err); ok && err != nil {
+		switch len(errs) {
 		case 1:
-			return agg.Errors()[0]
+			return errs[0]
 		case 0:
 			return nil
 		}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAggregateAs(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "/etc/app.yaml", Err: os.ErrNotExist}
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{NewAggregate([]error{io.EOF, pathErr}), true},
+		{NewAggregate([]error{io.EOF, NewAggregate([]error{fmt.Errorf("load: %w", pathErr)})}), true},
+		{NewAggregate([]error{io.EOF, multiError{New("decode"), pathErr}}), true},
+		{multiError{io.EOF, NewAggregate([]error{pathErr})}, true},
+		{NewAggregate([]error{io.EOF, New("decode")}), false},
+	}
+
+	for i, tt := range tests {
+		var target *os.PathError
+		got := As(tt.err, &target)
+		if got != tt.want {
+			t.Errorf("test %d: As(%v): got %t, want %t", i+1, tt.err, got, tt.want)
+		}
+		if got && target != pathErr {
+			t.Errorf("test %d: As(%v): got target %v, want %v", i+1, tt.err, target, pathErr)
+		}
+		if agg, ok := tt.err.(aggregate); ok {
+			target = nil
+			if agg.As(&target) != tt.want {
+				t.Errorf("test %d: aggregate.As(%v): got %t, want %t", i+1, tt.err, !tt.want, tt.want)
+			}
+		}
+	}
+}
+
+func TestAggregateIs(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{NewAggregate([]error{io.EOF}), true},
+		{NewAggregate([]error{New("a"), multiError{New("b"), io.EOF}}), true},
+		{NewAggregate([]error{New("a"), testAggregate{multiError{io.EOF}}}), true},
+		{NewAggregate([]error{New("a"), New("b")}), false},
+	}
+
+	for i, tt := range tests {
+		if got := Is(tt.err, io.EOF); got != tt.want {
+			t.Errorf("test %d: Is(%v): got %t, want %t", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateUnwrap(t *testing.T) {
+	a, b := New("a"), New("b")
+	agg := NewAggregate([]error{a, b}).(aggregate)
+
+	errs := agg.Unwrap()
+	if !reflect.DeepEqual(errs, []error{a, b}) {
+		t.Errorf("Unwrap(): got %v, want [a b]", errs)
+	}
+	errs[0] = nil
+	if agg[0] != a {
+		t.Errorf("Unwrap(): modifying the result changed the aggregate")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	a, b, c, d := New("a"), New("b"), New("c"), New("d")
+
+	tests := []struct {
+		agg  Aggregate
+		want []error
+	}{
+		{nil, nil},
+		{NewAggregate([]error{a, b}), []error{a, b}},
+		{NewAggregate([]error{a, NewAggregate([]error{b, NewAggregate([]error{c})})}), []error{a, b, c}},
+		{NewAggregate([]error{a, multiError{b, multiError{c, nil}}, d}), []error{a, b, c, d}},
+		{NewAggregate([]error{testAggregate{multiError{a, b}}, c}), []error{a, b, c}},
+		{NewAggregate([]error{multiError{}}), nil},
+	}
+
+	for i, tt := range tests {
+		got := Flatten(tt.agg)
+		var errs []error
+		if got != nil {
+			errs = got.Errors()
+		}
+		if !reflect.DeepEqual(errs, tt.want) {
+			t.Errorf("test %d: Flatten(%v): got %v, want %v", i+1, tt.agg, errs, tt.want)
+		}
+	}
+}
+
+func TestFilterOut(t *testing.T) {
+	isEOF := func(err error) bool { return Is(err, io.EOF) }
+	a := New("a")
+
+	tests := []struct {
+		err  error
+		want []error
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{a, []error{a}},
+		{NewAggregate([]error{io.EOF, a}), []error{a}},
+		{multiError{io.EOF, a}, []error{a}},
+		{NewAggregate([]error{multiError{io.EOF, fmt.Errorf("read: %w", io.EOF)}, a}), []error{a}},
+		{multiError{io.EOF, NewAggregate([]error{io.EOF})}, nil},
+	}
+
+	for i, tt := range tests {
+		got := FilterOut(tt.err, isEOF)
+		var errs []error
+		switch got := got.(type) {
+		case nil:
+		case Aggregate:
+			errs = Flatten(got).Errors()
+		default:
+			errs = []error{got}
+		}
+		if !reflect.DeepEqual(errs, tt.want) {
+			t.Errorf("test %d: FilterOut(%v): got %v, want %v", i+1, tt.err, errs, tt.want)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	a, b := New("a"), New("b")
+
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{nil, nil},
+		{a, a},
+		{NewAggregate([]error{a}), a},
+		{multiError{a}, a},
+		{multiError{}, nil},
+	}
+
+	for i, tt := range tests {
+		if got := Reduce(tt.err); got != tt.want {
+			t.Errorf("test %d: Reduce(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	agg := NewAggregate([]error{a, b})
+	if got := Reduce(agg); !reflect.DeepEqual(got, agg) {
+		t.Errorf("Reduce(%v): got %v, want the aggregate", agg, got)
+	}
+}
+
+// testAggregate is an Aggregate implemented outside of this package.
+type testAggregate []error
+
+func (agg testAggregate) Error() string     { return fmt.Sprint([]error(agg)) }
+func (agg testAggregate) Errors() []error   { return agg }
+func (agg testAggregate) Is(err error) bool { return false }
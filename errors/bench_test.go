@@ -191,3 +191,29 @@ func BenchmarkCodeFormatting(b *testing.B) {
 	}
 	GlobalE = stackStr
 }
+
+func BenchmarkStackPolicy(b *testing.B) {
+	runs := []struct {
+		name   string
+		policy StackPolicy
+	}{
+		{"default", StackPolicy{}},
+		{"disabled", StackPolicy{Disabled: true}},
+		{"depth-8", StackPolicy{Depth: 8}},
+		{"sample-100", StackPolicy{SampleEvery: 100}},
+	}
+	defer SetStackPolicy(StackPolicy{})
+
+	for _, r := range runs {
+		b.Run(r.name, func(b *testing.B) {
+			SetStackPolicy(r.policy)
+			var err error
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err = marmotErrors(0, 10)
+			}
+			b.StopTimer()
+			GlobalE = err
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogFormat is the encoding of an error code catalog.
+type CatalogFormat string
+
+// Supported catalog encodings.
+const (
+	CatalogYAML CatalogFormat = "yaml"
+	CatalogJSON CatalogFormat = "json"
+)
+
+// CoderSpec is the declarative description of a single error code.
+type CoderSpec struct {
+	// Code is the integer code of the error.
+	Code int `json:"code" yaml:"code"`
+
+	// HTTPStatus is the HTTP status that should be used for the error code.
+	HTTPStatus int `json:"httpStatus,omitempty" yaml:"httpStatus,omitempty"`
+
+	// Message is the external (user) facing error text.
+	Message string `json:"message" yaml:"message"`
+
+	// Reference specify the reference document.
+	Reference string `json:"reference,omitempty" yaml:"reference,omitempty"`
+
+	// Messages contains the translations of Message, keyed by locale.
+	Messages map[string]string `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// Catalog is a declarative list of error codes, shared between the Go
+// registry and anything else that needs to know about the codes.
+type Catalog struct {
+	Codes []CoderSpec `json:"codes" yaml:"codes"`
+}
+
+// ParseCatalog decodes a catalog in the given format.
+func ParseCatalog(data []byte, format CatalogFormat) (*Catalog, error) {
+	catalog := &Catalog{}
+
+	var err error
+	switch format {
+	case CatalogYAML:
+		err = yaml.Unmarshal(data, catalog)
+	case CatalogJSON:
+		err = json.Unmarshal(data, catalog)
+	default:
+		return nil, Errorf("unsupported catalog format %q", format)
+	}
+	if err != nil {
+		return nil, Wrapf(err, "decode %s catalog", format)
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// Validate checks the catalog for reserved, duplicated and malformed codes.
+func (c *Catalog) Validate() error {
+	seen := make(map[int]struct{}, len(c.Codes))
+	for _, spec := range c.Codes {
+		if spec.Code == 0 {
+			return Errorf("code `0` is reserved by `github.com/coding-hui/common/errors` as unknownCode error code")
+		}
+		if _, ok := seen[spec.Code]; ok {
+			return Errorf("code: %d is declared more than once in the catalog", spec.Code)
+		}
+		seen[spec.Code] = struct{}{}
+
+		if spec.HTTPStatus != 0 && http.StatusText(spec.HTTPStatus) == "" {
+			return Errorf("code: %d has an invalid HTTP status %d", spec.Code, spec.HTTPStatus)
+		}
+	}
+
+	return nil
+}
+
+// Coder converts the spec into a Coder implementation.
+func (s CoderSpec) Coder() Coder {
+	return defaultCoder{s.Code, s.HTTPStatus, s.Message, s.Reference}
+}
+
+// Coders converts the catalog entries into Coder implementations.
+func (c *Catalog) Coders() []Coder {
+	coders := make([]Coder, 0, len(c.Codes))
+	for _, spec := range c.Codes {
+		coders = append(coders, spec.Coder())
+	}
+
+	return coders
+}
+
+// Marshal encodes the catalog in the given format.
+func (c *Catalog) Marshal(format CatalogFormat) ([]byte, error) {
+	switch format {
+	case CatalogYAML:
+		return yaml.Marshal(c)
+	case CatalogJSON:
+		return json.MarshalIndent(c, "", "  ")
+	default:
+		return nil, Errorf("unsupported catalog format %q", format)
+	}
+}
+
+// LoadCatalog parses a catalog and registers all of its codes. The codes
+// within a declared CodeRange are registered with their owning range, the
+// most specific declared range containing them. Like MustRegister, it refuses
+// to override codes that already exist, but reports the conflict as an error
+// instead of panicking. Either every code of the catalog is registered or
+// none is.
+func LoadCatalog(data []byte, format CatalogFormat) error {
+	catalog, err := ParseCatalog(data, format)
+	if err != nil {
+		return err
+	}
+
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	for _, spec := range catalog.Codes {
+		if _, ok := codes[spec.Code]; ok {
+			return Errorf("code: %d already exist", spec.Code)
+		}
+	}
+	for _, coder := range catalog.Coders() {
+		codes[coder.Code()] = coder
+	}
+	for _, spec := range catalog.Codes {
+		for locale, msg := range spec.Messages {
+			RegisterMessages(locale, map[int]string{spec.Code: msg})
+		}
+	}
+
+	return nil
+}
+
+// LoadCatalogFile loads a catalog from a file. The format is derived from the
+// file extension: `.json` is decoded as JSON, anything else as YAML.
+func LoadCatalogFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Wrapf(err, "read catalog %s", path)
+	}
+
+	return LoadCatalog(data, catalogFormatOf(path))
+}
+
+// ExportCatalog returns a catalog of every registered code, sorted by code.
+// The built-in codes of the package are left out, so that the catalog can be
+// loaded back with LoadCatalog.
+func ExportCatalog() *Catalog {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	localeMux.RLock()
+	defer localeMux.RUnlock()
+
+	catalog := &Catalog{Codes: make([]CoderSpec, 0, len(codes))}
+	for _, coder := range codes {
+		if coder.Code() == unknownCoder.Code() {
+			continue
+		}
+		spec := CoderSpec{
+			Code:       coder.Code(),
+			HTTPStatus: coder.HTTPStatus(),
+			Message:    coder.String(),
+			Reference:  coder.Reference(),
+		}
+		for locale, table := range messages {
+			if msg, ok := table[coder.Code()]; ok {
+				if spec.Messages == nil {
+					spec.Messages = map[string]string{}
+				}
+				spec.Messages[locale] = msg
+			}
+		}
+		catalog.Codes = append(catalog.Codes, spec)
+	}
+	sort.Slice(catalog.Codes, func(i, j int) bool {
+		return catalog.Codes[i].Code < catalog.Codes[j].Code
+	})
+
+	return catalog
+}
+
+func catalogFormatOf(path string) CatalogFormat {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return CatalogJSON
+	}
+
+	return CatalogYAML
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		data    string
+		format  CatalogFormat
+		want    int
+		wantErr bool
+	}{
+		{"codes:\n- code: 900001\n  httpStatus: 404\n  message: Not found\n", CatalogYAML, 1, false},
+		{`{"codes":[{"code":900001,"httpStatus":404,"message":"Not found"}]}`, CatalogJSON, 1, false},
+		{"codes:\n- code: 0\n  message: reserved\n", CatalogYAML, 0, true},
+		{"codes:\n- code: 900001\n- code: 900001\n", CatalogYAML, 0, true},
+		{"codes:\n- code: 900001\n  httpStatus: 999\n", CatalogYAML, 0, true},
+		{`{"codes":`, CatalogJSON, 0, true},
+		{"", CatalogFormat("toml"), 0, true},
+	}
+
+	for i, tt := range tests {
+		catalog, err := ParseCatalog([]byte(tt.data), tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("test %d: ParseCatalog(): got error %v, want error %v", i+1, err, tt.wantErr)
+			continue
+		}
+		if err == nil && len(catalog.Codes) != tt.want {
+			t.Errorf("test %d: ParseCatalog(): got %d codes, want %d", i+1, len(catalog.Codes), tt.want)
+		}
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "codes.json")
+	data := `{"codes":[
+		{"code":900101,"httpStatus":404,"message":"User not found","reference":"https://example.com/900101",
+		 "messages":{"zh-CN":"用户不存在"}},
+		{"code":900102,"message":"User already exist"}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadCatalogFile(path); err != nil {
+		t.Fatalf("LoadCatalogFile(): %v", err)
+	}
+	if err := LoadCatalogFile(path); err == nil {
+		t.Errorf("LoadCatalogFile(): expected error on duplicate registration")
+	}
+
+	coder := ParseCoder(WithCode(900101, "user 1 not found"))
+	if coder.HTTPStatus() != 404 || coder.String() != "User not found" ||
+		coder.Reference() != "https://example.com/900101" {
+		t.Errorf("ParseCoder(): got %d %q %q", coder.HTTPStatus(), coder.String(), coder.Reference())
+	}
+	if got := ParseCoderLocalized(WithCode(900101, ""), "zh-CN").String(); got != "用户不存在" {
+		t.Errorf("ParseCoderLocalized(): got %q, want %q", got, "用户不存在")
+	}
+	if got := ParseCoder(WithCode(900102, "")).HTTPStatus(); got != 500 {
+		t.Errorf("ParseCoder(): got HTTP status %d, want 500", got)
+	}
+
+	catalog := ExportCatalog()
+	var found int
+	for i, spec := range catalog.Codes {
+		if i > 0 && catalog.Codes[i-1].Code >= spec.Code {
+			t.Errorf("ExportCatalog(): codes are not sorted: %d before %d", catalog.Codes[i-1].Code, spec.Code)
+		}
+		if spec.Code == 900101 || spec.Code == 900102 {
+			found++
+		}
+		if spec.Code == 900101 && spec.Messages["zh-cn"] != "用户不存在" {
+			t.Errorf("ExportCatalog(): got messages %v", spec.Messages)
+		}
+	}
+	if found != 2 {
+		t.Errorf("ExportCatalog(): got %d loaded codes, want 2", found)
+	}
+
+	for _, format := range []CatalogFormat{CatalogYAML, CatalogJSON} {
+		out, err := catalog.Marshal(format)
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", format, err)
+		}
+		parsed, err := ParseCatalog(out, format)
+		if err != nil {
+			t.Fatalf("ParseCatalog(%s): %v", format, err)
+		}
+		if len(parsed.Codes) != len(catalog.Codes) {
+			t.Errorf("round trip %s: got %d codes, want %d", format, len(parsed.Codes), len(catalog.Codes))
+		}
+	}
+}
+
+func TestExportCatalogRoundTrip(t *testing.T) {
+	data := `{"codes":[{"code":900201,"httpStatus":409,"message":"Conflict","messages":{"zh":"冲突"}}]}`
+	if err := LoadCatalog([]byte(data), CatalogJSON); err != nil {
+		t.Fatalf("LoadCatalog(): %v", err)
+	}
+	r := MustRegisterRange(CodeRange{Service: "catalog", Min: 900300, Max: 900399})
+	r.MustRegister(defaultCoder{900301, 404, "Entry not found", ""})
+
+	catalog := ExportCatalog()
+	for _, spec := range catalog.Codes {
+		if spec.Code == unknownCoder.Code() {
+			t.Errorf("ExportCatalog(): got built-in code %d", spec.Code)
+		}
+	}
+	out, err := catalog.Marshal(CatalogYAML)
+	if err != nil {
+		t.Fatalf("Marshal(): %v", err)
+	}
+
+	// load the catalog into a registry with only the built-in codes, but
+	// with the same declared ranges
+	codeMux.Lock()
+	savedCodes := codes
+	codes = map[int]Coder{unknownCoder.Code(): unknownCoder}
+	codeMux.Unlock()
+	defer func() {
+		codeMux.Lock()
+		codes = savedCodes
+		codeMux.Unlock()
+	}()
+
+	if err := LoadCatalog(out, CatalogYAML); err != nil {
+		t.Fatalf("LoadCatalog(ExportCatalog()): %v", err)
+	}
+	if got := ExportCatalog(); !reflect.DeepEqual(got, catalog) {
+		t.Errorf("round trip: got %+v, want %+v", got, catalog)
+	}
+	if coders := r.Codes(); len(coders) != 1 || coders[0].Code() != 900301 {
+		t.Errorf("Codes(): got %v, want the code of the range", coders)
+	}
+}
@@ -81,6 +81,7 @@ var codeMux = &sync.Mutex{}
 
 // Register register a user define error code.
 // It will overrid the exist code.
+// It will panic when the code lies within a declared CodeRange.
 func Register(coder Coder) {
 	if coder.Code() == 0 {
 		panic("code `0` is reserved by `github.com/coding-hui/common/errors` as unknownCode error code")
@@ -89,11 +90,16 @@ func Register(coder Coder) {
 	codeMux.Lock()
 	defer codeMux.Unlock()
 
+	if err := checkUnranged(coder.Code()); err != nil {
+		panic(err.Error())
+	}
+
 	codes[coder.Code()] = coder
 }
 
 // MustRegister register a user define error code.
-// It will panic when the same Code already exist.
+// It will panic when the same Code already exist, or when the code lies
+// within a declared CodeRange.
 func MustRegister(coder Coder) {
 	if coder.Code() == 0 {
 		panic("code '0' is reserved by 'github.com/coding-hui/common/errors' as ErrUnknown error code")
@@ -102,6 +108,10 @@ func MustRegister(coder Coder) {
 	codeMux.Lock()
 	defer codeMux.Unlock()
 
+	if err := checkUnranged(coder.Code()); err != nil {
+		panic(err.Error())
+	}
+
 	if _, ok := codes[coder.Code()]; ok {
 		panic(fmt.Sprintf("code: %d already exist", coder.Code()))
 	}
@@ -109,36 +119,115 @@ func MustRegister(coder Coder) {
 	codes[coder.Code()] = coder
 }
 
+// CodeOrder determines which code wins when an error chain carries more
+// than one code.
+type CodeOrder int
+
+const (
+	// OutermostCode selects the code closest to the top of the chain, i.e.
+	// the code that errors.As would find first.
+	OutermostCode CodeOrder = iota
+
+	// InnermostCode selects the code closest to the root cause along the
+	// branch of the outermost code.
+	InnermostCode
+)
+
 // ParseCoder parse any error into *withCode.
 // nil error will return nil direct.
-// None withStack error will be parsed as ErrUnknown.
+// The error chain, including errors wrapped by fmt.Errorf("%w") and the
+// errors of an Aggregate, is searched for the outermost code. Errors without
+// a registered code will be parsed as ErrUnknown.
 func ParseCoder(err error) Coder {
+	return ParseCoderWithOrder(err, OutermostCode)
+}
+
+// ParseCoderWithOrder is like ParseCoder, but lets the caller choose whether
+// the outermost or the innermost code of the chain wins.
+func ParseCoderWithOrder(err error, order CodeOrder) Coder {
 	if err == nil {
 		return nil
 	}
 
-	if v, ok := err.(*withCode); ok {
-		if coder, ok := codes[v.code]; ok {
-			return coder
-		}
+	if v := findCode(err, order); v != nil {
+		return v.lookupCoder()
+	}
+
+	return unknownCoder
+}
+
+// lookupCoder returns the registered coder of the code, the coder carried by
+// the error or ErrUnknown.
+func (w *withCode) lookupCoder() Coder {
+	if coder, ok := codes[w.code]; ok {
+		return coder
+	}
+	if w.coder != nil {
+		return w.coder
 	}
 
 	return unknownCoder
 }
 
 // IsCode reports whether any error in err's chain contains the given error code.
+// The chain includes errors wrapped by fmt.Errorf("%w"), multi-errors and the
+// errors of an Aggregate.
 func IsCode(err error, code int) bool {
-	if v, ok := err.(*withCode); ok {
-		if v.code == code {
-			return true
-		}
+	return walk(err, func(e error) bool {
+		v, ok := e.(*withCode)
+		return ok && v.code == code
+	})
+}
 
-		if v.cause != nil {
-			return IsCode(v.cause, code)
-		}
+// findCode returns the outermost or innermost *withCode of the error tree.
+func findCode(err error, order CodeOrder) *withCode {
+	var found *withCode
+	walk(err, func(e error) bool {
+		found, _ = e.(*withCode)
+		return found != nil
+	})
+
+	if found == nil || order != InnermostCode || found.cause == nil {
+		return found
+	}
+
+	if inner := findCode(found.cause, order); inner != nil {
+		return inner
+	}
 
+	return found
+}
+
+// walk calls fn for err and for every error of its tree in depth-first
+// pre-order, the order used by errors.Is and errors.As, until fn returns true.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
 		return false
 	}
+	if fn(err) {
+		return true
+	}
+
+	switch e := err.(type) {
+	case *withStack:
+		return walk(e.error, fn)
+	case interface{ Unwrap() []error }:
+		for _, nested := range e.Unwrap() {
+			if walk(nested, fn) {
+				return true
+			}
+		}
+	case Aggregate:
+		for _, nested := range e.Errors() {
+			if walk(nested, fn) {
+				return true
+			}
+		}
+	case interface{ Unwrap() error }:
+		return walk(e.Unwrap(), fn)
+	case interface{ Cause() error }:
+		return walk(e.Cause(), fn)
+	}
 
 	return false
 }
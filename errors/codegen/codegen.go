@@ -0,0 +1,268 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// annotationRegexp matches the `<http status>: <message>` code annotation.
+var annotationRegexp = regexp.MustCompile(`^\s*([1-5][0-9]{2})\s*:\s*(.+?)\s*$`)
+
+// errorCode is an annotated error code constant.
+type errorCode struct {
+	name    string
+	code    int
+	http    int
+	message string
+	pos     token.Position
+}
+
+// codePackage holds the annotated error codes of a single package.
+type codePackage struct {
+	name  string
+	codes []errorCode
+}
+
+// parsePackage parses and type checks the non-test Go files in dir and
+// collects the annotated constants of the given type.
+func parsePackage(dir, typeName string) (*codePackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		if len(files) > 0 && file.Name.Name != files[0].Name.Name {
+			return nil, fmt.Errorf("multiple packages in %s: %s and %s", dir, files[0].Name.Name, file.Name.Name)
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+
+	// Type errors are tolerated: the package may not compile before its code
+	// is generated. Constants whose value can't be determined are reported
+	// by constValue.
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil), Error: func(error) {}}
+	_, _ = conf.Check(filepath.Clean(dir), fset, files, info)
+
+	pkg := &codePackage{name: files[0].Name.Name}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vspec := spec.(*ast.ValueSpec)
+				annotation, ok := findAnnotation(vspec)
+				if !ok {
+					continue
+				}
+				for _, ident := range vspec.Names {
+					code, ok, err := constValue(info, ident, typeName)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", fset.Position(ident.Pos()), err)
+					}
+					if !ok {
+						continue
+					}
+					annotation.name = ident.Name
+					annotation.code = code
+					annotation.pos = fset.Position(ident.Pos())
+					pkg.codes = append(pkg.codes, annotation)
+				}
+			}
+		}
+	}
+
+	if err := checkDuplicates(pkg.codes); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pkg.codes, func(i, j int) bool { return pkg.codes[i].code < pkg.codes[j].code })
+
+	return pkg, nil
+}
+
+// isGenerated reports whether the file carries the standard
+// "Code generated ... DO NOT EDIT." header.
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			return false
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, "// Code generated ") && strings.HasSuffix(comment.Text, " DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// findAnnotation looks for the code annotation in the trailing comment first
+// and in the last line of the doc comment second.
+func findAnnotation(spec *ast.ValueSpec) (errorCode, bool) {
+	for _, group := range []*ast.CommentGroup{spec.Comment, spec.Doc} {
+		if group == nil {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(group.Text()), "\n")
+		if m := annotationRegexp.FindStringSubmatch(lines[len(lines)-1]); m != nil {
+			status, _ := strconv.Atoi(m[1])
+			if http.StatusText(status) == "" {
+				continue
+			}
+			return errorCode{http: status, message: m[2]}, true
+		}
+	}
+
+	return errorCode{}, false
+}
+
+// constValue returns the integer value of ident if it is a constant of the
+// given type.
+func constValue(info *types.Info, ident *ast.Ident, typeName string) (int, bool, error) {
+	obj, ok := info.Defs[ident].(*types.Const)
+	if !ok || typeNameOf(obj.Type()) != typeName {
+		return 0, false, nil
+	}
+
+	value, exact := constant.Int64Val(constant.ToInt(obj.Val()))
+	if !exact {
+		return 0, false, fmt.Errorf("%s is not an integer constant", ident.Name)
+	}
+	if value == 0 {
+		return 0, false, fmt.Errorf("%s: code `0` is reserved as the unknown error code", ident.Name)
+	}
+
+	return int(value), true, nil
+}
+
+// typeNameOf returns the unqualified name of a basic or named type.
+func typeNameOf(typ types.Type) string {
+	switch t := typ.(type) {
+	case *types.Basic:
+		return t.Name()
+	case *types.Named:
+		return t.Obj().Name()
+	default:
+		return ""
+	}
+}
+
+// checkDuplicates reports every code that is declared more than once.
+func checkDuplicates(codes []errorCode) error {
+	seen := map[int]errorCode{}
+	var msgs []string
+	for _, c := range codes {
+		if prev, ok := seen[c.code]; ok {
+			msgs = append(msgs, fmt.Sprintf("%s: code %d of %s is already used by %s (%s)",
+				c.pos, c.code, c.name, prev.name, prev.pos))
+			continue
+		}
+		seen[c.code] = c
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("duplicate error codes:\n\t%s", strings.Join(msgs, "\n\t"))
+	}
+
+	return nil
+}
+
+// generateSource renders the Coder implementation and its registration.
+func generateSource(pkg *codePackage, coderName, reference, args string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by \"codegen %s\"; DO NOT EDIT.\n\n", args)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg.name)
+	fmt.Fprintf(&buf, "import \"github.com/coding-hui/common/errors\"\n\n")
+	fmt.Fprintf(&buf, "// %s implements errors.Coder for the error codes of this package.\n", coderName)
+	fmt.Fprintf(&buf, "type %s struct {\n\tcode int\n\thttp int\n\text string\n\tref string\n}\n\n", coderName)
+	fmt.Fprintf(&buf, "var _ errors.Coder = %s{}\n\n", coderName)
+	fmt.Fprintf(&buf, "// Code returns the integer code of the coder.\n")
+	fmt.Fprintf(&buf, "func (c %s) Code() int { return c.code }\n\n", coderName)
+	fmt.Fprintf(&buf, "// HTTPStatus returns the associated HTTP status code.\n")
+	fmt.Fprintf(&buf, "func (c %s) HTTPStatus() int { return c.http }\n\n", coderName)
+	fmt.Fprintf(&buf, "// String returns the external error message.\n")
+	fmt.Fprintf(&buf, "func (c %s) String() string { return c.ext }\n\n", coderName)
+	fmt.Fprintf(&buf, "// Reference returns the reference document.\n")
+	fmt.Fprintf(&buf, "func (c %s) Reference() string { return c.ref }\n\n", coderName)
+	fmt.Fprintf(&buf, "func init() {\n")
+	for _, c := range pkg.codes {
+		fmt.Fprintf(&buf, "\terrors.MustRegister(%s{int(%s), %d, %q, %q})\n",
+			coderName, c.name, c.http, c.message, referenceOf(reference, c.code))
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("internal error: invalid Go generated: %w\n%s", err, buf.String())
+	}
+
+	return src, nil
+}
+
+// generateDoc renders a Markdown table of the error codes.
+func generateDoc(pkg *codePackage, reference string) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# Error codes\n\n")
+	fmt.Fprintf(&buf, "| Identifier | Code | HTTP Status | Description |\n")
+	fmt.Fprintf(&buf, "| ---------- | ---- | ----------- | ----------- |\n")
+	for _, c := range pkg.codes {
+		message := strings.ReplaceAll(c.message, "|", `\|`)
+		if ref := referenceOf(reference, c.code); ref != "" {
+			message = fmt.Sprintf("[%s](%s)", message, ref)
+		}
+		fmt.Fprintf(&buf, "| %s | %d | %d | %s |\n", c.name, c.code, c.http, message)
+	}
+
+	return buf.Bytes()
+}
+
+func referenceOf(format string, code int) string {
+	if format == "" {
+		return ""
+	}
+	if !strings.Contains(format, "%") {
+		return format
+	}
+
+	return fmt.Sprintf(format, code)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCodes = `package code
+
+type Code int
+
+const (
+	// ErrUserNotFound - user does not exist.
+	ErrUserNotFound int = 110001 // 404: User not found
+
+	// ErrUserAlreadyExist - user already exist.
+	// 400: User already exist
+	ErrUserAlreadyExist int = 110002
+
+	// ErrBase is not annotated and ignored.
+	ErrBase int = 110000
+)
+
+const (
+	ErrSecretNotFound Code = iota + 110101 // 404: Secret not found
+	ErrSecretExpired                       // 401: Secret expired
+)
+`
+
+func writePackage(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestParsePackage(t *testing.T) {
+	dir := writePackage(t, map[string]string{"code.go": testCodes})
+
+	tests := []struct {
+		typeName string
+		want     []errorCode
+	}{
+		{
+			"int",
+			[]errorCode{
+				{name: "ErrUserNotFound", code: 110001, http: 404, message: "User not found"},
+				{name: "ErrUserAlreadyExist", code: 110002, http: 400, message: "User already exist"},
+			},
+		},
+		{
+			"Code",
+			[]errorCode{
+				{name: "ErrSecretNotFound", code: 110101, http: 404, message: "Secret not found"},
+				{name: "ErrSecretExpired", code: 110102, http: 401, message: "Secret expired"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		pkg, err := parsePackage(dir, tt.typeName)
+		if err != nil {
+			t.Fatalf("parsePackage(%s): %v", tt.typeName, err)
+		}
+		if len(pkg.codes) != len(tt.want) {
+			t.Fatalf("parsePackage(%s): got %d codes, want %d", tt.typeName, len(pkg.codes), len(tt.want))
+		}
+		for i, want := range tt.want {
+			got := pkg.codes[i]
+			if got.name != want.name || got.code != want.code || got.http != want.http || got.message != want.message {
+				t.Errorf("parsePackage(%s): code %d: got %+v, want %+v", tt.typeName, i, got, want)
+			}
+		}
+	}
+}
+
+func TestParsePackageDuplicates(t *testing.T) {
+	dir := writePackage(t, map[string]string{
+		"a.go": "package code\n\nconst ErrA int = 100001 // 400: A\n",
+		"b.go": "package code\n\nconst ErrB int = 100001 // 400: B\n",
+	})
+
+	_, err := parsePackage(dir, "int")
+	if err == nil || !strings.Contains(err.Error(), "ErrB is already used by ErrA") {
+		t.Errorf("parsePackage(): got %v, want duplicate error", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := writePackage(t, map[string]string{
+		"code.go":               testCodes,
+		"int_code_generated.go": "// Code generated by \"codegen\"; DO NOT EDIT.\n\npackage code\n\nconst ErrStale int = 110001 // 404: Stale\n",
+	})
+
+	pkg, err := parsePackage(dir, "int")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generateSource(pkg, "errCoder", "https://example.com/errors#%d", "-type=int")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by \"codegen -type=int\"; DO NOT EDIT.",
+		"func (c errCoder) HTTPStatus() int",
+		`errors.MustRegister(errCoder{int(ErrUserNotFound), 404, "User not found", "https://example.com/errors#110001"})`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generateSource(): missing %q in\n%s", want, src)
+		}
+	}
+
+	doc := string(generateDoc(pkg, ""))
+	if want := "| ErrUserAlreadyExist | 110002 | 400 | User already exist |"; !strings.Contains(doc, want) {
+		t.Errorf("generateDoc(): missing %q in\n%s", want, doc)
+	}
+}
+
+// moduleImporter imports the packages from the module of the test, whatever
+// the directory of the package being checked.
+type moduleImporter struct {
+	types.ImporterFrom
+	dir string
+}
+
+func (i moduleImporter) ImportFrom(path, _ string, mode types.ImportMode) (*types.Package, error) {
+	return i.ImporterFrom.ImportFrom(path, i.dir, mode)
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	for _, typeName := range []string{"int", "Code"} {
+		dir := writePackage(t, map[string]string{"code.go": testCodes})
+		pkg, err := parsePackage(dir, typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := generateSource(pkg, "errCoder", "", "-type="+typeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fset := token.NewFileSet()
+		var files []*ast.File
+		for name, data := range map[string]string{"code.go": testCodes, "generated.go": string(src)} {
+			file, err := parser.ParseFile(fset, filepath.Join(dir, name), data, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files = append(files, file)
+		}
+
+		wd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		imp := moduleImporter{importer.ForCompiler(fset, "source", nil).(types.ImporterFrom), wd}
+		conf := types.Config{Importer: imp}
+		if _, err := conf.Check(dir, fset, files, nil); err != nil {
+			t.Errorf("generateSource(%s): generated code doesn't compile: %v\n%s", typeName, err, src)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Codegen generates github.com/coding-hui/common/errors.Coder implementations
+// from annotated error code constants.
+//
+// Given the name of an integer type T, codegen scans the package in the
+// current directory for constants of type T whose comment follows the
+// `<http status>: <message>` convention:
+//
+//	const (
+//		// ErrUserNotFound - user does not exist.
+//		ErrUserNotFound int = 110001 // 404: User not found
+//	)
+//
+// The annotation may be a trailing line comment or the last line of the doc
+// comment. Constants without an annotation are ignored. For every annotated
+// constant codegen emits a Coder and registers it with errors.MustRegister in
+// an init function. Duplicate codes are reported at generation time.
+//
+// Typically it is invoked by a go:generate directive:
+//
+//	//go:generate go run github.com/coding-hui/common/errors/codegen -type=int -doc=error_code.md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeName  = flag.String("type", "int", "type name of the error code constants")
+	output    = flag.String("output", "", "output file name; default srcdir/<type>_code_generated.go")
+	coderName = flag.String("coder", "errCoder", "name of the generated Coder implementation")
+	reference = flag.String("reference", "", "reference URL format of each code, e.g. https://example.com/errors#%d")
+	doc       = flag.String("doc", "", "also write a Markdown error code table to this file")
+)
+
+// Usage is a replacement usage function for the flags package.
+func Usage() {
+	fmt.Fprintf(os.Stderr, "Usage of codegen:\n")
+	fmt.Fprintf(os.Stderr, "\tcodegen [flags] [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("codegen: ")
+	flag.Usage = Usage
+	flag.Parse()
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	pkg, err := parsePackage(dir, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generateSource(pkg, *coderName, *reference, strings.Join(os.Args[1:], " "))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(*typeName)+"_code_generated.go")
+	}
+	if err := os.WriteFile(outputName, src, 0o644); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+
+	if *doc != "" {
+		if err := os.WriteFile(*doc, generateDoc(pkg, *reference), 0o644); err != nil {
+			log.Fatalf("writing doc: %s", err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Detailer is implemented by errors carrying structured key/value details,
+// such as a user ID, a resource name or a request ID. It can be used as the
+// target of As:
+//
+//	var d errors.Detailer
+//	if errors.As(err, &d) {
+//	        userID := d.Details()["user"]
+//	}
+type Detailer interface {
+	// Details returns the details of the error and of the errors it wraps.
+	// The details of outer errors take precedence.
+	Details() map[string]interface{}
+}
+
+var _ Detailer = &withDetails{}
+
+// withDetails annotates an error with key/value details.
+type withDetails struct {
+	cause error
+	kv    []interface{}
+}
+
+// WithDetails returns an error annotating err with the alternating key/value
+// pairs of kv, e.g. WithDetails(err, "user", 42, "resource", "pods").
+// Keys which aren't strings are converted with fmt.Sprint, and a key without
+// value has the nil value. The details survive wrapping: they are returned by
+// Details and Detail, and printed by %-v, %+v and their JSON forms.
+// If err is nil, WithDetails returns nil.
+func WithDetails(err error, kv ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	pairs := make([]interface{}, 0, len(kv)+len(kv)%2)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		var value interface{}
+		if i+1 < len(kv) {
+			value = kv[i+1]
+		}
+		pairs = append(pairs, key, value)
+	}
+
+	return &withDetails{cause: err, kv: pairs}
+}
+
+func (w *withDetails) Error() string { return w.cause.Error() }
+func (w *withDetails) Cause() error  { return w.cause }
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withDetails) Unwrap() error { return w.cause }
+
+// Details implements Detailer.
+func (w *withDetails) Details() map[string]interface{} {
+	details := map[string]interface{}{}
+	for err := error(w); err != nil; err = nextCause(err) {
+		d, ok := err.(*withDetails)
+		if !ok {
+			continue
+		}
+		for i := 0; i < len(d.kv); i += 2 {
+			if _, ok := details[d.kv[i].(string)]; !ok {
+				details[d.kv[i].(string)] = d.kv[i+1]
+			}
+		}
+	}
+
+	return details
+}
+
+func (w *withDetails) Format(s fmt.State, verb rune) {
+	if _, ok := unwrapDetails(w).(*withCode); ok {
+		formatChain(s, verb, w)
+		return
+	}
+
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v\n", w.cause)
+			io.WriteString(s, detailsText(w.kv))
+			return
+		}
+		if s.Flag('-') {
+			fmt.Fprintf(s, "%v %s", w.cause, detailsText(w.kv))
+			return
+		}
+		fallthrough
+	case 's', 'q':
+		io.WriteString(s, w.Error())
+	}
+}
+
+// Details returns the details of the error chain of err, or nil if it has
+// none.
+func Details(err error) map[string]interface{} {
+	var d Detailer
+	if As(err, &d) {
+		return d.Details()
+	}
+
+	return nil
+}
+
+// Detail returns the value of the detail key of the error chain of err, if
+// it is present and of type T.
+func Detail[T any](err error, key string) (T, bool) {
+	var zero T
+
+	v, ok := Details(err)[key]
+	if !ok {
+		return zero, false
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, false
+	}
+
+	return t, true
+}
+
+// nextCause returns the error wrapped by err, or nil.
+func nextCause(err error) error {
+	switch e := err.(type) {
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	}
+
+	return nil
+}
+
+// unwrapDetails returns the first error of the chain of err which isn't a
+// withDetails.
+func unwrapDetails(err error) error {
+	for {
+		w, ok := err.(*withDetails)
+		if !ok {
+			return err
+		}
+		err = w.cause
+	}
+}
+
+// splitDetails returns the first error of the chain of err which isn't a
+// withDetails, and the details of the withDetails errors before it.
+func splitDetails(err error) (error, []interface{}) {
+	var kv []interface{}
+	for {
+		w, ok := err.(*withDetails)
+		if !ok {
+			return err, kv
+		}
+		kv = append(kv, w.kv...)
+		err = w.cause
+	}
+}
+
+// keepDetails annotates err with the details kv, if there are any.
+func keepDetails(err error, kv []interface{}) error {
+	if len(kv) == 0 {
+		return err
+	}
+
+	return &withDetails{cause: err, kv: kv}
+}
+
+// detailsText renders the key/value pairs as `{key=value, key=value}`,
+// redacted with the package-level redaction policy.
+func detailsText(kv []interface{}) string {
+	policy := currentRedactionPolicy()
+
+	pairs := make([]string, 0, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key := kv[i].(string)
+		if policy.IsSensitiveKey(key) {
+			continue
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, redactValue(policy, kv[i+1])))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// detailsMap returns the key/value pairs as a map, redacted with the
+// package-level redaction policy.
+func detailsMap(kv []interface{}) map[string]interface{} {
+	policy := currentRedactionPolicy()
+
+	details := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key := kv[i].(string)
+		if policy.IsSensitiveKey(key) {
+			continue
+		}
+		details[key] = redactValue(policy, kv[i+1])
+	}
+
+	return details
+}
+
+func redactValue(policy *RedactionPolicy, v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return policy.Redact(s)
+	}
+
+	return v
+}
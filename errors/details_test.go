@@ -0,0 +1,154 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithDetailsNil(t *testing.T) {
+	if err := WithDetails(nil, "user", 42); err != nil {
+		t.Errorf("WithDetails(nil, ...): got %v, want nil", err)
+	}
+}
+
+func TestDetails(t *testing.T) {
+	tests := []struct {
+		err  error
+		want map[string]interface{}
+	}{
+		{io.EOF, nil},
+		{WithDetails(io.EOF), map[string]interface{}{}},
+		{WithDetails(io.EOF, "user", 42, "resource"), map[string]interface{}{"user": 42, "resource": nil}},
+		{WithDetails(io.EOF, 1, "one"), map[string]interface{}{"1": "one"}},
+		{
+			Wrap(WithDetails(WithCode(ConfigurationNotValid, "invalid"), "user", 42), "load"),
+			map[string]interface{}{"user": 42},
+		},
+		{
+			WithDetails(WrapC(WithDetails(io.EOF, "user", 42, "request", "r1"), ConfigurationNotValid, "read"), "user", 7),
+			map[string]interface{}{"user": 7, "request": "r1"},
+		},
+	}
+
+	for i, tt := range tests {
+		if got := Details(tt.err); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: Details(%v): got %v, want %v", i+1, tt.err, got, tt.want)
+		}
+	}
+
+	err := Wrap(WithDetails(io.EOF, "user", 42, "resource", "pods"), "read")
+	if user, ok := Detail[int](err, "user"); !ok || user != 42 {
+		t.Errorf("Detail[int](user): got %d, %t, want 42, true", user, ok)
+	}
+	if resource, ok := Detail[string](err, "resource"); !ok || resource != "pods" {
+		t.Errorf("Detail[string](resource): got %q, %t, want pods, true", resource, ok)
+	}
+	if _, ok := Detail[string](err, "user"); ok {
+		t.Errorf("Detail[string](user): got true, want false")
+	}
+	if _, ok := Detail[int](err, "missing"); ok {
+		t.Errorf("Detail[int](missing): got true, want false")
+	}
+
+	var d Detailer
+	if !As(err, &d) || d.Details()["user"] != 42 {
+		t.Errorf("As(Detailer): got %v, want the details", d)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Is(): expected the cause to be found")
+	}
+}
+
+func TestFormatDetails(t *testing.T) {
+	defer SetRedactionPolicy(GetRedactionPolicy())
+	SetRedactionPolicy(DefaultRedactionPolicy)
+
+	err := WithDetails(
+		WrapC(WithDetails(io.EOF, "file", "app.yaml"), ConfigurationNotValid, "read config"),
+		"user", 42, "email", "lk@example.com", "password", "s3cr3t",
+	)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "^ConfigurationNotValid error$"},
+		{"%v", "^ConfigurationNotValid error$"},
+		{"%-v", `^read config - #1 \[.+\] \(1000\) ConfigurationNotValid error \{user=42, email=\[REDACTED\]\}$`},
+		{"%+v", `^read config - #1 \[.+\] \(1000\) ConfigurationNotValid error \{user=42, email=\[REDACTED\]\}; ` +
+			`EOF - #0 EOF \{file=app.yaml\}$`},
+	}
+
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, err)
+		if !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("test %d: %s: got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	var data []map[string]interface{}
+	if err := json.Unmarshal([]byte(fmt.Sprintf("%#+v", err)), &data); err != nil {
+		t.Fatalf("%%#+v: %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("%%#+v: got %d entries, want 2", len(data))
+	}
+	if got, want := data[0]["details"], map[string]interface{}{"user": 42.0, "email": "[REDACTED]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%%#+v: got details %v, want %v", got, want)
+	}
+	if got, want := data[1]["details"], map[string]interface{}{"file": "app.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%%#+v: got details %v, want %v", got, want)
+	}
+	if got := fmt.Sprintf("%#v", err); strings.Contains(got, "details") {
+		t.Errorf("%%#v: got %s, want no details", got)
+	}
+
+	plain := WithDetails(New("boom"), "user", 42)
+	if got := fmt.Sprintf("%-v", plain); got != "boom {user=42}" {
+		t.Errorf("%%-v: got %q, want %q", got, "boom {user=42}")
+	}
+	if got := fmt.Sprintf("%+v", plain); !strings.HasPrefix(got, "boom\n") || !strings.HasSuffix(got, "\n{user=42}") {
+		t.Errorf("%%+v: got %q, want the stack and the details", got)
+	}
+}
+
+func TestFormatWrapDetails(t *testing.T) {
+	d := WithDetails(WithCode(ConfigurationNotValid, "db down"), "user", 42)
+
+	tests := []struct {
+		err    error
+		format string
+		want   string
+	}{
+		{Wrap(d, "ctx"), "%s", "^ConfigurationNotValid error$"},
+		{Wrap(d, "ctx"), "%-v", `^ctx - #1 \[.+\] \(1000\) ConfigurationNotValid error \{user=42\}$`},
+		{Wrapf(d, "ctx %d", 1), "%-v", `^ctx 1 - #1 \[.+\] \(1000\) ConfigurationNotValid error \{user=42\}$`},
+		{WithStack(d), "%-v", `^db down - #1 \[.+\] \(1000\) ConfigurationNotValid error \{user=42\}$`},
+		{Wrap(d, "ctx"), "%#-v", `^\[\{"caller":"#1 .+","code":1000,"details":\{"user":42\},"error":"ctx",` +
+			`"message":"ConfigurationNotValid error"\}\]$`},
+	}
+
+	for i, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.err)
+		if !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("test %d: %s: got %q, want %q", i+1, tt.format, got, tt.want)
+		}
+	}
+
+	err := Wrap(d, "ctx")
+	if !IsCode(err, ConfigurationNotValid) || Details(err)["user"] != 42 {
+		t.Errorf("Wrap(): got code %d and details %v", ParseCoder(err).Code(), Details(err))
+	}
+	if got := InternalMessage(err); got != "ctx; db down" {
+		t.Errorf("InternalMessage(): got %q, want %q", got, "ctx; db down")
+	}
+}
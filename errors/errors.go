@@ -151,13 +151,16 @@ func WithStack(err error) error {
 		return nil
 	}
 
-	if e, ok := err.(*withCode); ok {
-		return &withCode{
+	// the details of a coded error are kept on top of the new one
+	cause, kv := splitDetails(err)
+	if e, ok := cause.(*withCode); ok {
+		return keepDetails(&withCode{
 			err:   e.err,
 			code:  e.code,
-			cause: err,
-			stack: callers(),
-		}
+			coder: e.coder,
+			cause: e,
+			stack: codeCallers(e.code),
+		}, kv)
 	}
 
 	return &withStack{
@@ -187,7 +190,7 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 	case 'v':
 		if s.Flag('+') {
 			fmt.Fprintf(s, "%+v", w.Cause())
-			w.stack.Format(s, verb)
+			w.stack.format(s, verb, stackOf(w.error))
 			return
 		}
 		fallthrough
@@ -205,13 +208,16 @@ func Wrap(err error, message string) error {
 	if err == nil {
 		return nil
 	}
-	if e, ok := err.(*withCode); ok {
-		return &withCode{
+	// the details of a coded error are kept on top of the new one
+	cause, kv := splitDetails(err)
+	if e, ok := cause.(*withCode); ok {
+		return keepDetails(&withCode{
 			err:   fmt.Errorf(message),
 			code:  e.code,
-			cause: err,
-			stack: callers(),
-		}
+			coder: e.coder,
+			cause: e,
+			stack: codeCallers(e.code),
+		}, kv)
 	}
 
 	err = &withMessage{
@@ -232,13 +238,16 @@ func Wrapf(err error, format string, args ...interface{}) error {
 		return nil
 	}
 
-	if e, ok := err.(*withCode); ok {
-		return &withCode{
+	// the details of a coded error are kept on top of the new one
+	cause, kv := splitDetails(err)
+	if e, ok := cause.(*withCode); ok {
+		return keepDetails(&withCode{
 			err:   fmt.Errorf(format, args...),
 			code:  e.code,
-			cause: err,
-			stack: callers(),
-		}
+			coder: e.coder,
+			cause: e,
+			stack: codeCallers(e.code),
+		}, kv)
 	}
 
 	err = &withMessage{
@@ -303,6 +312,7 @@ func (w *withMessage) Format(s fmt.State, verb rune) {
 type withCode struct {
 	err   error
 	code  int
+	coder Coder // used if code is not registered, can be nil
 	cause error
 	*stack
 }
@@ -311,7 +321,35 @@ func WithCode(code int, format string, args ...interface{}) error {
 	return &withCode{
 		err:   fmt.Errorf(format, args...),
 		code:  code,
-		stack: callers(),
+		stack: codeCallers(code),
+	}
+}
+
+// WithCoder is like WithCode with the code of coder, but coder is reported
+// by ParseCoder if the code is not registered, e.g. for an error received
+// from another service whose codes are unknown here.
+func WithCoder(coder Coder, format string, args ...interface{}) error {
+	return &withCode{
+		err:   fmt.Errorf(format, args...),
+		code:  coder.Code(),
+		coder: coder,
+		stack: codeCallers(coder.Code()),
+	}
+}
+
+// WrapCoder is like WrapC with the code of coder, and reports coder like
+// WithCoder if the code is not registered.
+func WrapCoder(err error, coder Coder, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{
+		err:   fmt.Errorf(format, args...),
+		code:  coder.Code(),
+		coder: coder,
+		cause: err,
+		stack: codeCallers(coder.Code()),
 	}
 }
 
@@ -324,7 +362,7 @@ func WrapC(err error, code int, format string, args ...interface{}) error {
 		err:   fmt.Errorf(format, args...),
 		code:  code,
 		cause: err,
-		stack: callers(),
+		stack: codeCallers(code),
 	}
 }
 
@@ -335,6 +335,20 @@ func TestParseCoder(t *testing.T) {
 			1,
 			"http://github.com/coding-hui/common/errors/README.md",
 		},
+		{
+			Wrap(WithCoder(defaultCoder{990404, 404, "Remote not found", "ref"}, "remote error"), "call remote"),
+			404,
+			"Remote not found",
+			990404,
+			"ref",
+		},
+		{
+			WithCoder(defaultCoder{unknownCoder.Code(), 404, "ignored", ""}, "registered code wins"),
+			500,
+			"An internal server error occurred",
+			1,
+			"http://github.com/coding-hui/common/errors/README.md",
+		},
 	}
 
 	for i, tt := range tests {
@@ -357,3 +371,68 @@ func TestParseCoder(t *testing.T) {
 	}
 
 }
+
+// multiError mimics the errors.Join multi-error of Go 1.20.
+type multiError []error
+
+func (m multiError) Error() string   { return fmt.Sprint([]error(m)) }
+func (m multiError) Unwrap() []error { return m }
+
+func TestParseCoderChain(t *testing.T) {
+	coded := loadConfig()
+
+	tests := []struct {
+		err       error
+		wantOuter int
+		wantInner int
+	}{
+		{coded, ConfigurationNotValid, ErrEOF},
+		{Wrap(coded, "wrap"), ConfigurationNotValid, ErrEOF},
+		{WithMessage(coded, "message"), ConfigurationNotValid, ErrEOF},
+		{WithStack(WithMessage(coded, "message")), ConfigurationNotValid, ErrEOF},
+		{fmt.Errorf("wrapped: %w", coded), ConfigurationNotValid, ErrEOF},
+		{Wrap(fmt.Errorf("wrapped: %w", readConfig()), "wrap"), ErrEOF, ErrEOF},
+		{NewAggregate([]error{io.EOF, fmt.Errorf("%w", readConfig()), coded}), ErrEOF, ErrEOF},
+		{WrapC(NewAggregate([]error{io.EOF, decodeConfig()}), ErrLoadConfigFailed, "load"), ErrLoadConfigFailed, ErrEOF},
+		{multiError{io.EOF, Wrap(decodeConfig(), "wrap")}, ErrInvalidJSON, ErrEOF},
+		{io.EOF, unknownCoder.Code(), unknownCoder.Code()},
+	}
+
+	for i, tt := range tests {
+		if got := ParseCoder(tt.err).Code(); got != tt.wantOuter {
+			t.Errorf("test %d: ParseCoder(): got %d, want %d", i+1, got, tt.wantOuter)
+		}
+		if got := ParseCoderWithOrder(tt.err, InnermostCode).Code(); got != tt.wantInner {
+			t.Errorf("test %d: ParseCoderWithOrder(InnermostCode): got %d, want %d", i+1, got, tt.wantInner)
+		}
+	}
+
+	if ParseCoder(nil) != nil {
+		t.Errorf("ParseCoder(nil): expected nil")
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+		want bool
+	}{
+		{nil, ErrEOF, false},
+		{io.EOF, ErrEOF, false},
+		{loadConfig(), ErrEOF, true},
+		{loadConfig(), ErrLoadConfigFailed, false},
+		{Wrap(loadConfig(), "wrap"), ErrInvalidJSON, true},
+		{WithMessage(readConfig(), "message"), ErrEOF, true},
+		{fmt.Errorf("wrapped: %w", readConfig()), ErrEOF, true},
+		{NewAggregate([]error{io.EOF, NewAggregate([]error{readConfig()})}), ErrEOF, true},
+		{NewAggregate([]error{io.EOF, New("no code")}), ErrEOF, false},
+		{multiError{io.EOF, fmt.Errorf("%w", readConfig())}, ErrEOF, true},
+	}
+
+	for i, tt := range tests {
+		if got := IsCode(tt.err, tt.code); got != tt.want {
+			t.Errorf("test %d: IsCode(%v, %d): got %v, want %v", i+1, tt.err, tt.code, got, tt.want)
+		}
+	}
+}
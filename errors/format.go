@@ -13,10 +13,26 @@ import (
 
 // formatInfo contains all the error information.
 type formatInfo struct {
-	code    int
-	message string
-	err     string
-	stack   *stack
+	code     int
+	message  string
+	err      string
+	internal bool // message is the internal message err
+	stack    *stack
+	details  []interface{}
+}
+
+// publicMessage returns the message, redacted if it's the internal message.
+func (f *formatInfo) publicMessage() string {
+	if f.internal {
+		return redact(f.message)
+	}
+
+	return f.message
+}
+
+// internalMessage returns the redacted internal message.
+func (f *formatInfo) internalMessage() string {
+	return redact(f.err)
 }
 
 // Format implements fmt.Formatter. https://golang.org/pkg/fmt/#hdr-Printing
@@ -26,6 +42,13 @@ type formatInfo struct {
 //	%s  - Returns the user-safe error string mapped to the error code or
 //	  ┊   the error message if none is specified.
 //	%v      Alias for %s
+//	%#s     Public JSON output without internal diagnostics, safe to send
+//	        to clients, e.g. {"code":100102,"message":"Internal Server Error"}
+//
+// The details attached by WithDetails are printed by %-v, %+v, %#-v and %#+v
+// after the message of the error they annotate, e.g. {user=42, resource=pods}.
+// Internal messages and details are redacted with the package-level
+// RedactionPolicy, if one is set.
 //
 // Flags:
 //
@@ -53,6 +76,11 @@ type formatInfo struct {
 // /home/lk/workspace/golang/src/github.com/coding-hui/common/iam/main.go:35 (main.newErrorB)","error":"error for
 // internal read A","message":"(#100104) Validation failed"}]
 func (w *withCode) Format(state fmt.State, verb rune) {
+	formatChain(state, verb, w)
+}
+
+// formatChain formats the coded error chain of err, see withCode.Format.
+func formatChain(state fmt.State, verb rune, err error) {
 	switch verb {
 	case 'v':
 		str := bytes.NewBuffer([]byte{})
@@ -76,10 +104,9 @@ func (w *withCode) Format(state fmt.State, verb rune) {
 		}
 
 		sep := ""
-		errs := list(w)
-		length := len(errs)
-		for k, e := range errs {
-			finfo := buildFormatInfo(e)
+		infos := buildFormatInfos(err)
+		length := len(infos)
+		for k, finfo := range infos {
 			jsonData, str = format(length-k-1, jsonData, str, finfo, sep, flagDetail, flagTrace, modeJSON)
 			sep = "; "
 
@@ -100,9 +127,16 @@ func (w *withCode) Format(state fmt.State, verb rune) {
 
 		fmt.Fprintf(state, "%s", strings.Trim(str.String(), "\r\n\t"))
 	default:
-		finfo := buildFormatInfo(w)
+		if verb == 's' && state.Flag('#') {
+			// Public JSON, safe to send over the wire
+			byts, _ := MarshalPublicJSON(err)
+			state.Write(byts)
+			return
+		}
+
+		finfo := buildFormatInfo(unwrapDetails(err))
 		// Externally-safe error message
-		fmt.Fprintf(state, finfo.message)
+		fmt.Fprintf(state, finfo.publicMessage())
 	}
 }
 
@@ -112,13 +146,13 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 		data := map[string]interface{}{}
 		if flagDetail || flagTrace {
 			data = map[string]interface{}{
-				"message": finfo.message,
+				"message": finfo.publicMessage(),
 				"code":    finfo.code,
-				"error":   finfo.err,
+				"error":   finfo.internalMessage(),
 			}
 
 			caller := fmt.Sprintf("#%d", k)
-			if finfo.stack != nil {
+			if finfo.stack != nil && len(*finfo.stack) > 0 {
 				f := Frame((*finfo.stack)[0])
 				caller = fmt.Sprintf("%s %s:%d (%s)",
 					caller,
@@ -128,30 +162,36 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 				)
 			}
 			data["caller"] = caller
+			if len(finfo.details) > 0 {
+				data["details"] = detailsMap(finfo.details)
+			}
 		} else {
-			data["error"] = finfo.message
+			data["error"] = finfo.publicMessage()
 		}
 		jsonData = append(jsonData, data)
 	} else {
 		if flagDetail || flagTrace {
-			if finfo.stack != nil {
+			if finfo.stack != nil && len(*finfo.stack) > 0 {
 				f := Frame((*finfo.stack)[0])
 				fmt.Fprintf(str, "%s%s - #%d [%s:%d (%s)] (%d) %s",
 					sep,
-					finfo.err,
+					finfo.internalMessage(),
 					k,
 					f.file(),
 					f.line(),
 					f.name(),
 					finfo.code,
-					finfo.message,
+					finfo.publicMessage(),
 				)
 			} else {
-				fmt.Fprintf(str, "%s%s - #%d %s", sep, finfo.err, k, finfo.message)
+				fmt.Fprintf(str, "%s%s - #%d %s", sep, finfo.internalMessage(), k, finfo.publicMessage())
+			}
+			if len(finfo.details) > 0 {
+				fmt.Fprintf(str, " %s", detailsText(finfo.details))
 			}
 
 		} else {
-			fmt.Fprintf(str, finfo.message)
+			fmt.Fprintf(str, finfo.publicMessage())
 		}
 	}
 
@@ -174,46 +214,65 @@ func list(e error) []error {
 	return ret
 }
 
+// buildFormatInfos returns the information of every error of the chain of e.
+// The details of withDetails errors are attached to the error they wrap.
+func buildFormatInfos(e error) []*formatInfo {
+	infos := []*formatInfo{}
+
+	var details []interface{}
+	for _, err := range list(e) {
+		if w, ok := err.(*withDetails); ok {
+			details = append(details, w.kv...)
+			continue
+		}
+
+		finfo := buildFormatInfo(err)
+		finfo.details = details
+		details = nil
+		infos = append(infos, finfo)
+	}
+
+	return infos
+}
+
 func buildFormatInfo(e error) *formatInfo {
 	var finfo *formatInfo
 
 	switch err := e.(type) {
 	case *fundamental:
 		finfo = &formatInfo{
-			code:    unknownCoder.Code(),
-			message: err.msg,
-			err:     err.msg,
-			stack:   err.stack,
+			code:     unknownCoder.Code(),
+			message:  err.msg,
+			err:      err.msg,
+			internal: true,
+			stack:    err.stack,
 		}
 	case *withStack:
 		finfo = &formatInfo{
-			code:    unknownCoder.Code(),
-			message: err.Error(),
-			err:     err.Error(),
-			stack:   err.stack,
+			code:     unknownCoder.Code(),
+			message:  err.Error(),
+			err:      err.Error(),
+			internal: true,
+			stack:    err.stack,
 		}
 	case *withCode:
-		coder, ok := codes[err.code]
-		if !ok {
-			coder = unknownCoder
-		}
-
-		extMsg := coder.String()
-		if extMsg == "" {
-			extMsg = err.err.Error()
-		}
+		coder := err.lookupCoder()
 
 		finfo = &formatInfo{
 			code:    coder.Code(),
-			message: extMsg,
+			message: coder.String(),
 			err:     err.err.Error(),
 			stack:   err.stack,
 		}
+		if finfo.message == "" {
+			finfo.message, finfo.internal = finfo.err, true
+		}
 	default:
 		finfo = &formatInfo{
-			code:    unknownCoder.Code(),
-			message: err.Error(),
-			err:     err.Error(),
+			code:     unknownCoder.Code(),
+			message:  err.Error(),
+			err:      err.Error(),
+			internal: true,
 		}
 	}
 
@@ -0,0 +1,145 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Group runs tasks in goroutines and collects their errors. Unlike
+// AggregateGoroutines, it bounds the number of concurrently running tasks,
+// can cancel the remaining work on the first error, and labels the errors
+// with the task which returned them.
+//
+// The zero value is a valid Group without limit, which collects every error.
+// A panicking task contributes an error instead of crashing the process.
+type Group struct {
+	// Limit is the maximum number of tasks running concurrently. Go blocks
+	// until a running task completes when the limit is reached. Zero means
+	// no limit. It must not be changed after the first call to Go.
+	Limit int
+
+	// FailFast cancels the context of the group on the first error and
+	// stops starting new tasks. Wait then returns only the first error.
+	FailFast bool
+
+	cancel func()
+	once   sync.Once
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	tasks  int
+	errs   []taskError
+	failed bool
+}
+
+// taskError is an error together with the order of the task returning it.
+type taskError struct {
+	index int
+	err   error
+}
+
+// NewGroup returns a new Group and an associated context derived from ctx.
+// The derived context is canceled the first time a task fails if FailFast is
+// set, or the first time Wait returns, whichever occurs first.
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls fn in a new goroutine.
+func (g *Group) Go(fn func() error) {
+	g.GoLabeled("", fn)
+}
+
+// GoLabeled calls fn in a new goroutine. The error returned by fn, if any, is
+// prefixed with label, so that the message of the aggregate says which task
+// failed.
+func (g *Group) GoLabeled(label string, fn func() error) {
+	g.once.Do(func() {
+		if g.Limit > 0 {
+			g.sem = make(chan struct{}, g.Limit)
+		}
+	})
+
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	if g.FailFast && g.failed {
+		g.mu.Unlock()
+		g.release()
+		return
+	}
+	index := g.tasks
+	g.tasks++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.release()
+
+		if err := Safe(fn)(); err != nil {
+			if label != "" {
+				err = WithMessagef(err, "%s: %v", label, err)
+			}
+			g.fail(index, err)
+		}
+	}()
+}
+
+// Wait blocks until all tasks have returned. If FailFast is set, it returns
+// the first error; otherwise it returns an Aggregate of every error, in the
+// order the tasks were started, or nil if all of them succeeded.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.FailFast {
+		return g.errs[0].err
+	}
+
+	sort.Slice(g.errs, func(i, j int) bool { return g.errs[i].index < g.errs[j].index })
+	errs := make([]error, 0, len(g.errs))
+	for _, e := range g.errs {
+		errs = append(errs, e.err)
+	}
+
+	return NewAggregate(errs)
+}
+
+func (g *Group) fail(index int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.FailFast && g.failed {
+		return
+	}
+	g.errs = append(g.errs, taskError{index: index, err: err})
+	g.failed = true
+	if g.FailFast && g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *Group) release() {
+	if g.sem != nil {
+		<-g.sem
+	}
+}
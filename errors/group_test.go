@@ -0,0 +1,114 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupLimit(t *testing.T) {
+	g := &Group{Limit: 3}
+
+	var running, peak int32
+	for i := 0; i < 20; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait(): got %v, want nil", err)
+	}
+	if peak > 3 {
+		t.Errorf("Wait(): got %d tasks running concurrently, want at most 3", peak)
+	}
+}
+
+func TestGroupCollectAll(t *testing.T) {
+	g, ctx := NewGroup(context.Background())
+
+	for i := 0; i < 5; i++ {
+		i := i
+		g.GoLabeled(fmt.Sprintf("task %d", i), func() error {
+			// finish in the reverse order to check the errors are sorted
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+			if i%2 == 1 {
+				return fmt.Errorf("failed %d", i)
+			}
+			return nil
+		})
+	}
+	g.Go(func() error { panic(io.EOF) })
+
+	err := g.Wait()
+	agg, ok := err.(Aggregate)
+	if !ok {
+		t.Fatalf("Wait(): got %T, want an Aggregate", err)
+	}
+	if got, want := agg.Error(), "[task 1: failed 1, task 3: failed 3, An internal server error occurred]"; got != want {
+		t.Errorf("Wait(): got %q, want %q", got, want)
+	}
+	if !Is(err, io.EOF) {
+		t.Errorf("Wait(): expected the panic value in the aggregate")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("Wait(): expected the context to be canceled")
+	}
+}
+
+func TestGroupFailFast(t *testing.T) {
+	g, ctx := NewGroup(context.Background())
+	g.Limit = 1
+	g.FailFast = true
+
+	var started int32
+	g.GoLabeled("first", func() error {
+		atomic.AddInt32(&started, 1)
+		return io.EOF
+	})
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			atomic.AddInt32(&started, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+
+	err := g.Wait()
+	if err == nil || err.Error() != "first: EOF" || !Is(err, io.EOF) {
+		t.Errorf("Wait(): got %v, want first: EOF", err)
+	}
+	if started != 1 {
+		t.Errorf("Wait(): got %d tasks started, want 1", started)
+	}
+}
+
+func TestGroupZeroValue(t *testing.T) {
+	var g Group
+
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait(): got %v, want nil", err)
+	}
+
+	g.Go(func() error { return io.EOF })
+	if err := g.Wait(); !Is(err, io.EOF) {
+		t.Errorf("Wait(): got %v, want EOF", err)
+	}
+}
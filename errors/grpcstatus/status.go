@@ -0,0 +1,252 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package grpcstatus converts coded errors to and from gRPC status values.
+//
+// The gRPC code of a coded error is derived from the HTTP status of its
+// Coder. The integer error code, the HTTP status and the reference document
+// travel in an errdetails.ErrorInfo detail, so the receiving side can restore
+// an error for which errors.IsCode and errors.ParseCoder keep working, even if
+// the code is not registered there.
+package grpcstatus
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/coding-hui/common/errors"
+)
+
+// Domain is the errdetails.ErrorInfo domain of coded errors.
+const Domain = "github.com/coding-hui/common/errors"
+
+// Metadata keys of the errdetails.ErrorInfo detail.
+const (
+	MetadataCode       = "code"
+	MetadataHTTPStatus = "httpStatus"
+	MetadataReference  = "reference"
+)
+
+// statusClientClosedRequest is the non-standard HTTP status used when the
+// client cancels the request.
+const statusClientClosedRequest = 499
+
+// httpToCode maps HTTP status codes to gRPC codes.
+var httpToCode = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusMethodNotAllowed:      codes.Unimplemented,
+	http.StatusRequestTimeout:        codes.DeadlineExceeded,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusGone:                  codes.NotFound,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.OutOfRange,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	statusClientClosedRequest:        codes.Canceled,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusBadGateway:            codes.Unavailable,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// codeToHTTP maps gRPC codes to HTTP status codes.
+var codeToHTTP = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           statusClientClosedRequest,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// CodeFromHTTP returns the gRPC code for the HTTP status code of an error.
+// Unknown client errors map to FailedPrecondition, unknown server errors to
+// Internal, and any other status, including the 2xx ones, to Unknown: an
+// error never maps to OK.
+func CodeFromHTTP(httpStatus int) codes.Code {
+	if code, ok := httpToCode[httpStatus]; ok {
+		return code
+	}
+
+	switch {
+	case httpStatus >= 400 && httpStatus < 500:
+		return codes.FailedPrecondition
+	case httpStatus >= 500:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// HTTPFromCode returns the HTTP status code for a gRPC code.
+func HTTPFromCode(code codes.Code) int {
+	if httpStatus, ok := codeToHTTP[code]; ok {
+		return httpStatus
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ToStatus converts err into a gRPC status. Errors which already carry a gRPC
+// status are returned as is. Any other error is converted using its Coder:
+// the message is the external error text and the integer code is attached as
+// an errdetails.ErrorInfo detail.
+// ToStatus returns nil if err is nil.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	if se, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return se.GRPCStatus()
+	}
+
+	coder := errors.ParseCoder(err)
+	st := status.New(CodeFromHTTP(coder.HTTPStatus()), coder.String())
+
+	metadata := map[string]string{
+		MetadataCode:       strconv.Itoa(coder.Code()),
+		MetadataHTTPStatus: strconv.Itoa(coder.HTTPStatus()),
+	}
+	if ref := coder.Reference(); ref != "" {
+		metadata[MetadataReference] = ref
+	}
+
+	detailed, derr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   strconv.Itoa(coder.Code()),
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+	if derr != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// FromStatus converts a gRPC status back into a coded error. If the code is
+// not registered locally, the error reports a Coder built from the status:
+// its message, and the HTTP status and reference of the
+// errdetails.ErrorInfo detail. Statuses without a coded detail are returned
+// as their plain status error. FromStatus returns nil for a nil or OK status.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	info, code, ok := codedInfo(st)
+	if !ok {
+		return st.Err()
+	}
+
+	httpStatus, err := strconv.Atoi(info.GetMetadata()[MetadataHTTPStatus])
+	if err != nil {
+		httpStatus = HTTPFromCode(st.Code())
+	}
+	coder := errors.CoderSpec{
+		Code:       code,
+		HTTPStatus: httpStatus,
+		Message:    st.Message(),
+		Reference:  info.GetMetadata()[MetadataReference],
+	}.Coder()
+
+	return errors.WithCoder(coder, "%s", st.Message())
+}
+
+// FromError converts an error received from a gRPC call back into a coded
+// error. Errors which don't carry a gRPC status are returned unchanged.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(st)
+}
+
+// CodeOf returns the integer error code carried by st, if any.
+func CodeOf(st *status.Status) (int, bool) {
+	_, code, ok := codedInfo(st)
+
+	return code, ok
+}
+
+// codedInfo returns the coded errdetails.ErrorInfo detail of st and its code.
+func codedInfo(st *status.Status) (*errdetails.ErrorInfo, int, bool) {
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+
+		code, err := strconv.Atoi(info.GetMetadata()[MetadataCode])
+		if err != nil {
+			continue
+		}
+
+		return info, code, true
+	}
+
+	return nil, 0, false
+}
+
+// UnaryServerInterceptor converts the errors returned by unary handlers into
+// gRPC statuses.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor converts the errors returned by stream handlers into
+// gRPC statuses.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err).Err()
+		}
+
+		return nil
+	}
+}
+
+// UnaryClientInterceptor converts the gRPC statuses returned by unary calls
+// back into coded errors.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
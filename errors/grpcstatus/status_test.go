@@ -0,0 +1,169 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package grpcstatus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/coding-hui/common/errors"
+)
+
+const errSecretNotFound int = 920001
+
+func init() {
+	catalog := `{"codes":[{"code":920001,"httpStatus":404,"message":"Secret not found",` +
+		`"reference":"https://example.com/errors/920001"}]}`
+	if err := errors.LoadCatalog([]byte(catalog), errors.CatalogJSON); err != nil {
+		panic(err)
+	}
+}
+
+func TestCodeFromHTTP(t *testing.T) {
+	tests := []struct {
+		http int
+		want codes.Code
+	}{
+		{http.StatusOK, codes.Unknown},
+		{http.StatusNoContent, codes.Unknown},
+		{http.StatusFound, codes.Unknown},
+		{http.StatusBadRequest, codes.InvalidArgument},
+		{http.StatusNotFound, codes.NotFound},
+		{http.StatusTeapot, codes.FailedPrecondition},
+		{http.StatusInternalServerError, codes.Internal},
+		{http.StatusLoopDetected, codes.Internal},
+		{http.StatusServiceUnavailable, codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		if got := CodeFromHTTP(tt.http); got != tt.want {
+			t.Errorf("CodeFromHTTP(%d): got %v, want %v", tt.http, got, tt.want)
+		}
+	}
+
+	if got := HTTPFromCode(codes.NotFound); got != http.StatusNotFound {
+		t.Errorf("HTTPFromCode(NotFound): got %d", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	err := errors.Wrap(errors.WithCode(errSecretNotFound, "secret %q not found", "db"), "get secret")
+
+	st := ToStatus(err)
+	if st.Code() != codes.NotFound || st.Message() != "Secret not found" {
+		t.Errorf("ToStatus(): got %v %q", st.Code(), st.Message())
+	}
+	if code, ok := CodeOf(st); !ok || code != errSecretNotFound {
+		t.Errorf("CodeOf(): got %d %v", code, ok)
+	}
+
+	// simulate the wire
+	back := FromError(status.FromProto(st.Proto()).Err())
+	if !errors.IsCode(back, errSecretNotFound) {
+		t.Errorf("FromError(): expected code %d in %v", errSecretNotFound, back)
+	}
+	if coder := errors.ParseCoder(back); coder.HTTPStatus() != http.StatusNotFound {
+		t.Errorf("FromError(): got HTTP status %d", coder.HTTPStatus())
+	}
+
+	if got := ToStatus(errors.New("pq: connection refused")); got.Code() != codes.Internal ||
+		got.Message() != "An internal server error occurred" {
+		t.Errorf("ToStatus(): got %v %q for uncoded error", got.Code(), got.Message())
+	}
+
+	plain := status.Error(codes.Unavailable, "unavailable")
+	if got := ToStatus(plain); got.Code() != codes.Unavailable {
+		t.Errorf("ToStatus(): got %v for status error", got.Code())
+	}
+	if got := FromError(plain); status.Code(got) != codes.Unavailable || errors.IsCode(got, errSecretNotFound) {
+		t.Errorf("FromError(): got %v, want plain status error", got)
+	}
+	if ToStatus(nil) != nil || FromError(nil) != nil {
+		t.Errorf("nil error must convert to nil")
+	}
+}
+
+func TestSuccessStatusCoder(t *testing.T) {
+	errors.Register(errors.CoderSpec{Code: 920002, HTTPStatus: http.StatusOK, Message: "Accepted"}.Coder())
+
+	st := ToStatus(errors.WithCode(920002, "not really a success"))
+	if st.Code() == codes.OK || st.Err() == nil {
+		t.Fatalf("ToStatus(): got %v, an error must never map to OK", st.Code())
+	}
+
+	server := UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.WithCode(920002, "not really a success")
+	}
+	if _, err := server(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err == nil {
+		t.Errorf("UnaryServerInterceptor(): a failed handler must not succeed")
+	}
+}
+
+func TestFromStatusUnregistered(t *testing.T) {
+	const code = 920003
+	st, err := status.New(codes.PermissionDenied, "Quota exceeded").WithDetails(&errdetails.ErrorInfo{
+		Reason: "920003",
+		Domain: Domain,
+		Metadata: map[string]string{
+			MetadataCode:       "920003",
+			MetadataHTTPStatus: "429",
+			MetadataReference:  "https://example.com/errors/920003",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back := FromError(status.FromProto(st.Proto()).Err())
+	if !errors.IsCode(back, code) {
+		t.Errorf("FromError(): expected code %d in %v", code, back)
+	}
+	coder := errors.ParseCoder(back)
+	if coder.Code() != code || coder.HTTPStatus() != http.StatusTooManyRequests ||
+		coder.String() != "Quota exceeded" || coder.Reference() != "https://example.com/errors/920003" {
+		t.Errorf("FromError(): got coder %d %d %q %q", coder.Code(), coder.HTTPStatus(), coder.String(),
+			coder.Reference())
+	}
+
+	// the coder survives wrapping and round-trips again
+	if got := ToStatus(errors.Wrap(back, "call quota service")); got.Code() != codes.ResourceExhausted {
+		t.Errorf("ToStatus(): got %v for a forwarded error", got.Code())
+	}
+
+	// without HTTP status, it's derived from the gRPC code
+	st, _ = status.New(codes.PermissionDenied, "Denied").WithDetails(&errdetails.ErrorInfo{
+		Domain:   Domain,
+		Metadata: map[string]string{MetadataCode: "920004"},
+	})
+	if coder := errors.ParseCoder(FromStatus(st)); coder.HTTPStatus() != http.StatusForbidden {
+		t.Errorf("FromStatus(): got HTTP status %d", coder.HTTPStatus())
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	server := UnaryServerInterceptor()
+	client := UnaryClientInterceptor()
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.WithCode(errSecretNotFound, "secret not found")
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		_, err := server(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	err := client(context.Background(), "/secret.v1/Get", nil, nil, nil, invoker)
+	if !errors.IsCode(err, errSecretNotFound) {
+		t.Errorf("interceptors: expected code %d in %v", errSecretNotFound, err)
+	}
+}
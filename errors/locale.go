@@ -0,0 +1,226 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LocalizedCoder is an optional interface of Coder which provides the
+// external (user) facing error text in several languages.
+type LocalizedCoder interface {
+	Coder
+
+	// LocalizedString returns the external error text for the given locale
+	// and whether such a translation exists.
+	LocalizedString(locale string) (string, bool)
+}
+
+// messages contains the registered translations, keyed by locale and code.
+var messages = map[string]map[int]string{}
+
+// localeFallbacks contains explicitly registered fallbacks of a locale.
+var localeFallbacks = map[string][]string{}
+var localeMux = &sync.RWMutex{}
+
+// RegisterMessages registers the external error texts of a locale, keyed by
+// error code. It will override the exist translations.
+func RegisterMessages(locale string, msgs map[int]string) {
+	locale = normalizeLocale(locale)
+
+	localeMux.Lock()
+	defer localeMux.Unlock()
+
+	table, ok := messages[locale]
+	if !ok {
+		table = make(map[int]string, len(msgs))
+		messages[locale] = table
+	}
+	for code, msg := range msgs {
+		table[code] = msg
+	}
+}
+
+// RegisterLocaleFallback registers the locales to try, in order, when no
+// translation exists for locale. Fallbacks are consulted before the locale is
+// truncated, e.g. "zh-TW" may fall back to "zh-Hant" before "zh".
+func RegisterLocaleFallback(locale string, fallbacks ...string) {
+	normalized := make([]string, 0, len(fallbacks))
+	for _, l := range fallbacks {
+		normalized = append(normalized, normalizeLocale(l))
+	}
+
+	localeMux.Lock()
+	defer localeMux.Unlock()
+
+	localeFallbacks[normalizeLocale(locale)] = normalized
+}
+
+// LocalizedString returns the external error text of coder for the first of
+// the preferred locales that has a translation. Translations provided by a
+// LocalizedCoder take precedence over registered ones. If none of the locales
+// has a translation, coder.String() is returned.
+func LocalizedString(coder Coder, locales ...string) string {
+	if coder == nil {
+		return ""
+	}
+
+	type candidate struct {
+		locale string
+		msg    string
+		ok     bool
+	}
+
+	code := coder.Code()
+	candidates := []candidate{}
+
+	localeMux.RLock()
+	for _, locale := range locales {
+		for _, l := range localeChain(locale) {
+			msg, ok := messages[l][code]
+			candidates = append(candidates, candidate{l, msg, ok})
+		}
+	}
+	localeMux.RUnlock()
+
+	// the LocalizedCoder is called without the lock: it may register
+	// messages or locales
+	lc, isLocalized := coder.(LocalizedCoder)
+	for _, c := range candidates {
+		if isLocalized {
+			if msg, ok := lc.LocalizedString(c.locale); ok {
+				return msg
+			}
+		}
+		if c.ok {
+			return c.msg
+		}
+	}
+
+	return coder.String()
+}
+
+// ParseCoderLocalized parse any error into a Coder like ParseCoder, but with
+// String() returning the external error text in the first of the preferred
+// locales that has a translation.
+func ParseCoderLocalized(err error, locales ...string) Coder {
+	coder := ParseCoder(err)
+	if coder == nil || len(locales) == 0 {
+		return coder
+	}
+
+	return localizedCoder{Coder: coder, msg: LocalizedString(coder, locales...)}
+}
+
+// ParseCoderForRequest parse any error into a Coder localized according to
+// the Accept-Language header of r.
+func ParseCoderForRequest(err error, r *http.Request) Coder {
+	return ParseCoderLocalized(err, AcceptLanguages(r)...)
+}
+
+// AcceptLanguages returns the locales of the Accept-Language header of r,
+// ordered by preference. Locales with a zero quality and the "*" wildcard are
+// omitted.
+func AcceptLanguages(r *http.Request) []string {
+	if r == nil {
+		return nil
+	}
+
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var langs []weighted
+	for _, header := range r.Header.Values("Accept-Language") {
+		for _, part := range strings.Split(header, ",") {
+			params := strings.Split(part, ";")
+			locale := strings.TrimSpace(params[0])
+			if locale == "" || locale == "*" {
+				continue
+			}
+
+			q := 1.0
+			for _, param := range params[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+				v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					v = 0
+				}
+				q = v
+			}
+			if q <= 0 {
+				continue
+			}
+
+			langs = append(langs, weighted{locale, q})
+		}
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	locales := make([]string, 0, len(langs))
+	for _, l := range langs {
+		locales = append(locales, l.locale)
+	}
+
+	return locales
+}
+
+// localizedCoder overrides the external error text of a Coder.
+type localizedCoder struct {
+	Coder
+	msg string
+}
+
+// String returns the localized external error message.
+func (coder localizedCoder) String() string {
+	return coder.msg
+}
+
+// localeChain returns the locales to look up for locale, the locale itself
+// first, followed by its registered fallbacks and its truncations, e.g.
+// "zh-Hant-TW" yields "zh-hant-tw", "zh-hant", "zh".
+// localeMux must be held by the caller.
+func localeChain(locale string) []string {
+	locale = normalizeLocale(locale)
+	if locale == "" {
+		return nil
+	}
+
+	chain := []string{locale}
+	seen := map[string]struct{}{locale: {}}
+	add := func(l string) {
+		if _, ok := seen[l]; !ok && l != "" {
+			seen[l] = struct{}{}
+			chain = append(chain, l)
+		}
+	}
+
+	for _, l := range localeFallbacks[locale] {
+		add(l)
+	}
+	for i := strings.LastIndex(locale, "-"); i > 0; i = strings.LastIndex(locale, "-") {
+		locale = locale[:i]
+		add(locale)
+		for _, l := range localeFallbacks[locale] {
+			add(l)
+		}
+	}
+
+	return chain
+}
+
+// normalizeLocale converts a locale into its lower-case, hyphenated form.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testLocalizedCoder struct {
+	defaultCoder
+	msgs map[string]string
+}
+
+func (coder testLocalizedCoder) LocalizedString(locale string) (string, bool) {
+	msg, ok := coder.msgs[locale]
+	return msg, ok
+}
+
+func TestAcceptLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"zh-CN", []string{"zh-CN"}},
+		{"en;q=0.5, zh-CN, zh;q=0.8", []string{"zh-CN", "zh", "en"}},
+		{"fr;q=0, *;q=0.1, de", []string{"de"}},
+	}
+
+	for i, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set("Accept-Language", tt.header)
+		}
+		if got := AcceptLanguages(r); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("test %d: AcceptLanguages(%q): got %q, want %q", i+1, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizedString(t *testing.T) {
+	RegisterMessages("zh", map[int]string{ConfigurationNotValid: "配置无效"})
+	RegisterMessages("zh_Hant", map[int]string{ConfigurationNotValid: "配置無效"})
+	RegisterLocaleFallback("zh-TW", "zh-Hant")
+
+	coder := codes[ConfigurationNotValid]
+	localized := testLocalizedCoder{
+		defaultCoder: defaultCoder{ConfigurationNotValid, 500, "ConfigurationNotValid error", ""},
+		msgs:         map[string]string{"ja": "設定が無効です"},
+	}
+
+	tests := []struct {
+		coder   Coder
+		locales []string
+		want    string
+	}{
+		{coder, nil, "ConfigurationNotValid error"},
+		{coder, []string{"zh-CN"}, "配置无效"},
+		{coder, []string{"zh-TW"}, "配置無效"},
+		{coder, []string{"zh-Hant-HK"}, "配置無效"},
+		{coder, []string{"fr", "zh"}, "配置无效"},
+		{coder, []string{"fr"}, "ConfigurationNotValid error"},
+		{localized, []string{"ja-JP", "zh"}, "設定が無効です"},
+		{localized, []string{"en", "zh"}, "配置无效"},
+	}
+
+	for i, tt := range tests {
+		if got := LocalizedString(tt.coder, tt.locales...); got != tt.want {
+			t.Errorf("test %d: LocalizedString(%q): got %q, want %q", i+1, tt.locales, got, tt.want)
+		}
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "en;q=0.5, zh-CN")
+	got := ParseCoderForRequest(loadConfig(), r)
+	if got.String() != "配置无效" || got.Code() != ConfigurationNotValid || got.HTTPStatus() != 500 {
+		t.Errorf("ParseCoderForRequest(): got %d %d %q", got.Code(), got.HTTPStatus(), got.String())
+	}
+}
+
+// registeringCoder registers its messages when it's asked for one.
+type registeringCoder struct {
+	defaultCoder
+}
+
+func (coder registeringCoder) LocalizedString(locale string) (string, bool) {
+	RegisterMessages(locale, map[int]string{coder.C: coder.Ext})
+	return "", false
+}
+
+func TestLocalizedStringCallback(t *testing.T) {
+	coder := registeringCoder{defaultCoder{ConfigurationNotValid, 500, "ConfigurationNotValid error", ""}}
+
+	done := make(chan string)
+	go func() { done <- LocalizedString(coder, "de") }()
+
+	select {
+	case got := <-done:
+		if got != "ConfigurationNotValid error" {
+			t.Errorf("LocalizedString(): got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("LocalizedString(): deadlock calling the LocalizedCoder")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+var (
+	_ logr.Marshaler = &fundamental{}
+	_ logr.Marshaler = &withStack{}
+	_ logr.Marshaler = &withMessage{}
+	_ logr.Marshaler = &withCode{}
+	_ logr.Marshaler = &withDetails{}
+	_ logr.Marshaler = aggregate{}
+)
+
+// errorLog is the structured representation of an error chain, used by
+// structured loggers instead of a pre-rendered string.
+type errorLog struct {
+	// Error is the internal error message of this link of the chain.
+	Error string `json:"error"`
+
+	// Message is the external (user) facing error text.
+	Message string `json:"message,omitempty"`
+
+	// Code is the integer error code.
+	Code int `json:"code,omitempty"`
+
+	// HTTPStatus is the HTTP status associated with the error code.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+
+	// Caller is the innermost frame of the stack, formatted as
+	// `file:line (function)`.
+	Caller string `json:"caller,omitempty"`
+
+	// Stack is the stack trace captured by this link of the chain.
+	Stack []string `json:"stack,omitempty"`
+
+	// Details are the key/value details attached by WithDetails.
+	Details map[string]interface{} `json:"details,omitempty"`
+
+	// Cause is the next link of the chain.
+	Cause *errorLog `json:"cause,omitempty"`
+
+	// Errors are the errors of an Aggregate or multi-error.
+	Errors []*errorLog `json:"errors,omitempty"`
+}
+
+// MarshalLog implements logr.Marshaler.
+func (f *fundamental) MarshalLog() interface{} { return newErrorLog(f) }
+
+// MarshalLog implements logr.Marshaler.
+func (w *withStack) MarshalLog() interface{} { return newErrorLog(w) }
+
+// MarshalLog implements logr.Marshaler.
+func (w *withMessage) MarshalLog() interface{} { return newErrorLog(w) }
+
+// MarshalLog implements logr.Marshaler.
+func (w *withCode) MarshalLog() interface{} { return newErrorLog(w) }
+
+// MarshalLog implements logr.Marshaler.
+func (w *withDetails) MarshalLog() interface{} { return newErrorLog(w) }
+
+// MarshalLog implements logr.Marshaler.
+func (agg aggregate) MarshalLog() interface{} { return newErrorLog(agg) }
+
+// newErrorLog converts the error chain of err into an errorLog.
+func newErrorLog(err error) *errorLog {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *fundamental:
+		entry := &errorLog{Error: redact(e.msg)}
+		entry.setStack(e.stack)
+		return entry
+	case *withStack:
+		// withStack only annotates the wrapped error with a stack, so both
+		// are logged as a single link.
+		entry := newErrorLog(e.error)
+		if entry.Stack == nil {
+			entry.setStack(e.stack)
+		}
+		return entry
+	case *withDetails:
+		// withDetails only annotates the wrapped error with details, so both
+		// are logged as a single link.
+		entry := newErrorLog(e.cause)
+		for k, v := range detailsMap(e.kv) {
+			if entry.Details == nil {
+				entry.Details = map[string]interface{}{}
+			}
+			entry.Details[k] = v
+		}
+		return entry
+	case *withMessage:
+		return &errorLog{Error: redact(e.msg), Cause: newErrorLog(e.cause)}
+	case *withCode:
+		coder := e.lookupCoder()
+		entry := &errorLog{
+			Error:      redact(e.err.Error()),
+			Message:    coder.String(),
+			Code:       coder.Code(),
+			HTTPStatus: coder.HTTPStatus(),
+			Cause:      newErrorLog(e.cause),
+		}
+		entry.setStack(e.stack)
+		return entry
+	case interface{ Unwrap() []error }:
+		return newMultiErrorLog(err, e.Unwrap())
+	case Aggregate:
+		return newMultiErrorLog(err, e.Errors())
+	case interface{ Unwrap() error }:
+		return &errorLog{Error: redact(err.Error()), Cause: newErrorLog(e.Unwrap())}
+	default:
+		return &errorLog{Error: redact(err.Error())}
+	}
+}
+
+func newMultiErrorLog(err error, errs []error) *errorLog {
+	entry := &errorLog{Error: redact(err.Error())}
+	for _, nested := range errs {
+		if nested != nil {
+			entry.Errors = append(entry.Errors, newErrorLog(nested))
+		}
+	}
+
+	return entry
+}
+
+// setStack records the caller and the frames of s.
+func (entry *errorLog) setStack(s *stack) {
+	if s == nil || len(*s) == 0 {
+		return
+	}
+
+	f := Frame((*s)[0])
+	entry.Caller = fmt.Sprintf("%s:%d (%s)", f.file(), f.line(), f.name())
+	entry.Stack = make([]string, 0, len(*s))
+	for _, pc := range *s {
+		text, _ := Frame(pc).MarshalText()
+		entry.Stack = append(entry.Stack, string(text))
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestMarshalLog(t *testing.T) {
+	err := Wrap(loadConfig(), "start server")
+
+	entry, ok := err.(interface{ MarshalLog() interface{} }).MarshalLog().(*errorLog)
+	if !ok {
+		t.Fatalf("MarshalLog(): got %T, want *errorLog", entry)
+	}
+
+	var codes []int
+	for e := entry; e != nil; e = e.Cause {
+		codes = append(codes, e.Code)
+	}
+	want := []int{ConfigurationNotValid, ConfigurationNotValid, ErrInvalidJSON, ErrEOF, 0}
+	if len(codes) != len(want) {
+		t.Fatalf("MarshalLog(): got chain of codes %v, want %v", codes, want)
+	}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Errorf("MarshalLog(): got chain of codes %v, want %v", codes, want)
+			break
+		}
+	}
+
+	if entry.Error != "start server" || entry.HTTPStatus != 500 || entry.Message != "ConfigurationNotValid error" {
+		t.Errorf("MarshalLog(): got %+v", entry)
+	}
+	if !strings.Contains(entry.Caller, "log_test.go") || len(entry.Stack) == 0 {
+		t.Errorf("MarshalLog(): got caller %q with %d frames", entry.Caller, len(entry.Stack))
+	}
+	if inner := entry.Cause.Cause; !strings.Contains(inner.Caller, "errors.decodeConfig") {
+		t.Errorf("MarshalLog(): got inner caller %q", inner.Caller)
+	}
+
+	agg := NewAggregate([]error{io.EOF, New("boom")}).(aggregate).MarshalLog().(*errorLog)
+	if len(agg.Errors) != 2 || agg.Errors[1].Error != "boom" || agg.Errors[1].Caller == "" {
+		t.Errorf("MarshalLog(): got aggregate %+v", agg)
+	}
+
+	if _, err := json.Marshal(entry); err != nil {
+		t.Errorf("json.Marshal(): %v", err)
+	}
+}
+
+func TestLogr(t *testing.T) {
+	var out string
+	logger := funcr.NewJSON(func(obj string) { out = obj }, funcr.Options{})
+
+	logger.Error(nil, "load failed", "error", readConfig())
+
+	for _, want := range []string{`"code":1002`, `"httpStatus":500`, `"caller":`, `"cause":{"error":"read: end of input"}`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logr: missing %s in %s", want, out)
+		}
+	}
+}
+
+func TestMarshalLogDetails(t *testing.T) {
+	defer SetRedactionPolicy(GetRedactionPolicy())
+	SetRedactionPolicy(DefaultRedactionPolicy)
+
+	err := WithDetails(WithCode(ConfigurationNotValid, "invalid"), "user", 42, "token", "abc")
+
+	entry := err.(interface{ MarshalLog() interface{} }).MarshalLog().(*errorLog)
+	if entry.Code != ConfigurationNotValid || len(entry.Details) != 1 || entry.Details["user"] != 42 {
+		t.Errorf("MarshalLog(): got %+v, want the code and the details without token", entry)
+	}
+}
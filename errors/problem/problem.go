@@ -0,0 +1,248 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package problem renders coded errors as RFC 7807 problem details and parses
+// problem details back into coded errors.
+package problem
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/validation/field"
+)
+
+// ContentType is the media type of a problem details document.
+const ContentType = "application/problem+json"
+
+// defaultType is used when the error code has no reference document.
+const defaultType = "about:blank"
+
+// Problem is a problem details document as defined by RFC 7807, extended
+// with the error code, a trace ID and field-level validation failures.
+type Problem struct {
+	// Type is a URI reference that identifies the problem type.
+	Type string `json:"type,omitempty"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title,omitempty"`
+
+	// Status is the HTTP status code of the problem.
+	Status int `json:"status,omitempty"`
+
+	// Detail is a human-readable explanation specific to this occurrence.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI reference that identifies this occurrence.
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the integer error code.
+	Code int `json:"code,omitempty"`
+
+	// TraceID identifies the request that caused the problem.
+	TraceID string `json:"traceId,omitempty"`
+
+	// InvalidParams contains the field-level validation failures.
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam describes a single field that failed validation.
+type InvalidParam struct {
+	// Name is the path of the field, e.g. `spec.containers[0].name`.
+	Name string `json:"name"`
+
+	// Reason is a human-readable explanation of the failure.
+	Reason string `json:"reason"`
+
+	// Type is the machine readable field.ErrorType of the failure.
+	Type string `json:"type,omitempty"`
+
+	// Value is the offending value, if any.
+	Value interface{} `json:"value,omitempty"`
+
+	// Detail is the failure detail without the type and value.
+	Detail string `json:"detail,omitempty"`
+}
+
+// New renders err as a problem details document. The type, title, status and
+// code are taken from the Coder of err, the detail is its public message, see
+// errors.PublicMessage: the internal message of err is never sent to clients.
+// Field-level failures are collected
+// from any *field.Error, or errors.Aggregate of them, in the error chain.
+// New returns nil if err is nil.
+func New(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	return newProblem(err, errors.ParseCoder(err))
+}
+
+// NewForRequest is like New, but localizes the title according to the
+// Accept-Language header of r and uses the request path as the instance.
+func NewForRequest(err error, r *http.Request) *Problem {
+	if err == nil {
+		return nil
+	}
+
+	p := newProblem(err, errors.ParseCoderForRequest(err, r))
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.RequestURI()
+	}
+
+	return p
+}
+
+func newProblem(err error, coder errors.Coder) *Problem {
+	p := &Problem{
+		Type:   coder.Reference(),
+		Title:  coder.String(),
+		Status: coder.HTTPStatus(),
+		Detail: errors.PublicMessage(err),
+		Code:   coder.Code(),
+	}
+	if p.Type == "" {
+		p.Type = defaultType
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	for _, fe := range fieldErrors(err) {
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{
+			Name:   fe.Field,
+			Reason: fe.ErrorBody(),
+			Type:   string(fe.Type),
+			Value:  badValue(fe),
+			Detail: fe.Detail,
+		})
+	}
+
+	return p
+}
+
+// Write writes the problem to w with the problem+json content type and the
+// status of the problem.
+func (p *Problem) Write(w http.ResponseWriter) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "encode problem")
+	}
+
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+
+	return err
+}
+
+// Err converts the problem back into a coded error. The error message is the
+// detail of the problem, or its title if there is no detail. If the code of
+// the problem isn't registered, the error reports the status, type and title
+// of the problem as its Coder. Invalid params are restored as the
+// field.ErrorList cause of the returned error.
+func (p *Problem) Err() error {
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+
+	spec := errors.CoderSpec{
+		Code:       p.Code,
+		HTTPStatus: p.Status,
+		Message:    p.Title,
+	}
+	if p.Type != defaultType {
+		spec.Reference = p.Type
+	}
+	coder := spec.Coder()
+
+	if len(p.InvalidParams) == 0 {
+		return errors.WithCoder(coder, "%s", msg)
+	}
+
+	list := make(field.ErrorList, 0, len(p.InvalidParams))
+	for _, param := range p.InvalidParams {
+		list = append(list, &field.Error{
+			Type:     field.ErrorType(param.Type),
+			Field:    param.Name,
+			BadValue: param.Value,
+			Detail:   param.Detail,
+		})
+	}
+
+	return errors.WrapCoder(list.ToAggregate(), coder, "%s", msg)
+}
+
+// Parse decodes a problem details document.
+func Parse(data []byte) (*Problem, error) {
+	p := &Problem{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, errors.Wrap(err, "decode problem")
+	}
+
+	return p, nil
+}
+
+// FromResponse decodes the problem details document of a downstream
+// response. The status of the response is used when the document doesn't
+// carry one.
+func FromResponse(resp *http.Response) (*Problem, error) {
+	mediaType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	if mediaType != ContentType && mediaType != "application/json" {
+		return nil, errors.Errorf("unexpected content type %q, want %s", mediaType, ContentType)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "read problem")
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+
+	return p, nil
+}
+
+// fieldErrors collects the field errors of the error chain of err.
+func fieldErrors(err error) field.ErrorList {
+	var list field.ErrorList
+	for err != nil {
+		switch e := err.(type) {
+		case *field.Error:
+			list = append(list, e)
+		case errors.Aggregate:
+			for _, nested := range e.Errors() {
+				list = append(list, fieldErrors(nested)...)
+			}
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return list
+}
+
+// badValue returns the value of a field error that is worth reporting.
+func badValue(fe *field.Error) interface{} {
+	switch fe.Type {
+	case field.ErrorTypeRequired, field.ErrorTypeForbidden, field.ErrorTypeTooLong, field.ErrorTypeInternal:
+		return nil
+	default:
+		return fe.BadValue
+	}
+}
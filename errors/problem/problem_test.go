@@ -0,0 +1,172 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package problem
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/validation/field"
+)
+
+const (
+	errUserNotFound int = 910001 // 404: User not found
+	errInvalidUser  int = 910002 // 422: Invalid user
+)
+
+func init() {
+	catalog := `{"codes":[
+		{"code":910001,"httpStatus":404,"message":"User not found","reference":"https://example.com/errors/910001",
+		 "messages":{"zh":"用户不存在"}},
+		{"code":910002,"httpStatus":422,"message":"Invalid user"}
+	]}`
+	if err := errors.LoadCatalog([]byte(catalog), errors.CatalogJSON); err != nil {
+		panic(err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if New(nil) != nil {
+		t.Errorf("New(nil): expected nil")
+	}
+
+	p := New(errors.WithCode(errUserNotFound, "user %d not found in db", 1))
+	want := Problem{
+		Type:   "https://example.com/errors/910001",
+		Title:  "User not found",
+		Status: http.StatusNotFound,
+		Code:   errUserNotFound,
+	}
+	if p.Type != want.Type || p.Title != want.Title || p.Status != want.Status || p.Code != want.Code ||
+		p.Detail != "User not found" || len(p.InvalidParams) != 0 {
+		t.Errorf("New(): got %+v, want %+v", *p, want)
+	}
+
+	p = New(errors.New("boom"))
+	if p.Type != "http://github.com/coding-hui/common/errors/README.md" {
+		t.Errorf("New(): got type %q", p.Type)
+	}
+	if p.Status != http.StatusInternalServerError || p.Code != 1 || p.Detail != "An internal server error occurred" {
+		t.Errorf("New(): got status %d code %d detail %q, want 500, 1 and the public message", p.Status, p.Code, p.Detail)
+	}
+
+	p = New(stderrors.New("dial postgres://u:hunter2@db password=hunter2"))
+	if strings.Contains(p.Detail, "hunter2") {
+		t.Errorf("New(): got detail %q, want no internal message", p.Detail)
+	}
+}
+
+func TestNewForRequestWrite(t *testing.T) {
+	list := field.ErrorList{
+		field.Required(field.NewPath("metadata", "name"), ""),
+		field.Invalid(field.NewPath("spec", "port"), 70000, "must be a valid port"),
+	}
+	err := errors.WrapC(list.ToAggregate(), errInvalidUser, "validate user")
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/users?dryRun=true", nil)
+	r.Header.Set("Accept-Language", "zh-CN")
+	p := NewForRequest(err, r)
+	p.TraceID = "abc123"
+
+	if p.Instance != "/v1/users?dryRun=true" || p.Type != "about:blank" {
+		t.Errorf("NewForRequest(): got instance %q type %q", p.Instance, p.Type)
+	}
+	if len(p.InvalidParams) != 2 {
+		t.Fatalf("NewForRequest(): got %d invalid params, want 2", len(p.InvalidParams))
+	}
+	if got := p.InvalidParams[1]; got.Name != "spec.port" || got.Type != string(field.ErrorTypeInvalid) ||
+		got.Reason != "Invalid value: 70000: must be a valid port" {
+		t.Errorf("NewForRequest(): got invalid param %+v", got)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := p.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || rec.Header().Get("Content-Type") != ContentType {
+		t.Errorf("Write(): got status %d content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"type", "title", "status", "instance", "code", "traceId", "invalid-params"} {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("Write(): missing member %q in %s", key, rec.Body.String())
+		}
+	}
+
+	localized := NewForRequest(errors.WithCode(errUserNotFound, "not found"), r)
+	if localized.Title != "用户不存在" {
+		t.Errorf("NewForRequest(): got title %q, want localized title", localized.Title)
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	list := field.ErrorList{field.Invalid(field.NewPath("name"), "Bad Name", "must be lower case")}
+	if err := New(errors.WrapC(list.ToAggregate(), errInvalidUser, "validate")).Write(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := FromResponse(rec.Result())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := p.Err()
+	if !errors.IsCode(got, errInvalidUser) {
+		t.Errorf("Err(): expected code %d in %v", errInvalidUser, got)
+	}
+	if coder := errors.ParseCoder(got); coder.HTTPStatus() != http.StatusUnprocessableEntity {
+		t.Errorf("Err(): got HTTP status %d", coder.HTTPStatus())
+	}
+
+	params := fieldErrors(got)
+	if len(params) != 1 || params[0].Error() != list[0].Error() {
+		t.Errorf("Err(): got field errors %v, want %v", params, list)
+	}
+
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/html")
+	rec.WriteHeader(http.StatusBadGateway)
+	if _, err := FromResponse(rec.Result()); err == nil {
+		t.Errorf("FromResponse(): expected error for non problem content type")
+	}
+}
+
+func TestErrUnregistered(t *testing.T) {
+	p := &Problem{
+		Type:   "https://example.com/errors/920001",
+		Title:  "Quota exceeded",
+		Status: http.StatusTooManyRequests,
+		Detail: "quota of project demo exceeded",
+		Code:   920001,
+	}
+
+	err := p.Err()
+	if msg := errors.InternalMessage(err); msg != p.Detail {
+		t.Errorf("Err(): got message %q, want %q", msg, p.Detail)
+	}
+	coder := errors.ParseCoder(err)
+	if coder.Code() != p.Code || coder.HTTPStatus() != p.Status || coder.String() != p.Title ||
+		coder.Reference() != p.Type {
+		t.Errorf("Err(): got coder %d %d %q %q", coder.Code(), coder.HTTPStatus(), coder.String(), coder.Reference())
+	}
+
+	p.Type = defaultType
+	p.InvalidParams = []InvalidParam{{Name: "name", Type: string(field.ErrorTypeRequired)}}
+	got := New(p.Err())
+	if got.Type != defaultType || got.Status != p.Status || got.Title != p.Title || got.Code != p.Code ||
+		len(got.InvalidParams) != 1 {
+		t.Errorf("New(Err()): got %+v, want %+v", *got, *p)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CodeRange declares the inclusive range of error codes owned by a service,
+// or by a module of a service. Codes like 100101 conventionally encode the
+// service (10), the module (01) and a sequence number (01); declaring ranges
+// lets several teams share the global registry without collisions.
+//
+// Module ranges must lie within the range of their service, if the service
+// declares one, and must not overlap other ranges of the same service.
+// Ranges of different services must not overlap.
+//
+// The codes within a declared range are owned by the most specific range
+// containing them: Register and MustRegister refuse them, a service range
+// refuses the codes of its module ranges, and LoadCatalog registers them with
+// their owning range. A range can't be declared once codes within it are
+// registered.
+type CodeRange struct {
+	// Service is the name of the service owning the range.
+	Service string `json:"service" yaml:"service"`
+
+	// Module is the name of the module owning the range, or empty for the
+	// range of the whole service.
+	Module string `json:"module,omitempty" yaml:"module,omitempty"`
+
+	// Min is the smallest code of the range.
+	Min int `json:"min" yaml:"min"`
+
+	// Max is the largest code of the range.
+	Max int `json:"max" yaml:"max"`
+
+	// Owner is the team or person owning the codes.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+
+	// Description describes the codes of the range.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// codeRanges contains the declared code ranges. It's guarded by codeMux.
+var codeRanges []CodeRange
+
+// String returns the range as `service/module [min, max]`.
+func (r CodeRange) String() string {
+	name := r.Service
+	if r.Module != "" {
+		name += "/" + r.Module
+	}
+
+	return fmt.Sprintf("%s [%d, %d]", name, r.Min, r.Max)
+}
+
+// Contains reports whether code lies within the range.
+func (r CodeRange) Contains(code int) bool {
+	return r.Min <= code && code <= r.Max
+}
+
+func (r CodeRange) overlaps(o CodeRange) bool {
+	return r.Min <= o.Max && o.Min <= r.Max
+}
+
+func (r CodeRange) within(o CodeRange) bool {
+	return o.Min <= r.Min && r.Max <= o.Max
+}
+
+// RegisterRange declares a code range. It returns an error if the range is
+// malformed, already declared, collides with another range or contains
+// registered codes, which it wouldn't own.
+func RegisterRange(r CodeRange) error {
+	if r.Service == "" {
+		return Errorf("code range %s: service must be non-empty", r)
+	}
+	if r.Min <= unknownCoder.Code() || r.Min > r.Max {
+		return Errorf("code range %s: must satisfy %d < min <= max", r, unknownCoder.Code())
+	}
+
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	for _, e := range codeRanges {
+		switch {
+		case e.Service == r.Service && e.Module == r.Module:
+			return Errorf("code range %s: already declared as %s", r, e)
+		case e.Service != r.Service:
+			if e.overlaps(r) {
+				return Errorf("code range %s: overlaps %s", r, e)
+			}
+		case e.Module == "":
+			if !r.within(e) {
+				return Errorf("code range %s: exceeds the service range %s", r, e)
+			}
+		case r.Module == "":
+			if !e.within(r) {
+				return Errorf("code range %s: doesn't contain the module range %s", r, e)
+			}
+		default:
+			if e.overlaps(r) {
+				return Errorf("code range %s: overlaps %s", r, e)
+			}
+		}
+	}
+
+	var registered []int
+	for code := range codes {
+		if r.Contains(code) {
+			registered = append(registered, code)
+		}
+	}
+	if len(registered) > 0 {
+		sort.Ints(registered)
+		return Errorf("code range %s: contains the registered codes %v", r, registered)
+	}
+
+	codeRanges = append(codeRanges, r)
+
+	return nil
+}
+
+// MustRegisterRange declares a code range.
+// It will panic when the range can't be declared.
+func MustRegisterRange(r CodeRange) CodeRange {
+	if err := RegisterRange(r); err != nil {
+		panic(err.Error())
+	}
+
+	return r
+}
+
+// Register registers a user define error code within the range.
+// It fails when the code is outside of the range, when the range isn't
+// declared, when the code belongs to a more specific range, e.g. to a module
+// of the service of the range, or when the code already exist.
+func (r CodeRange) Register(coder Coder) error {
+	code := coder.Code()
+	if !r.Contains(code) {
+		return Errorf("code: %d is outside of the range %s", code, r)
+	}
+
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	if !r.declared() {
+		return Errorf("code range %s is not declared", r)
+	}
+	if owner, _ := rangeOf(code); owner != r {
+		return Errorf("code: %d belongs to the range %s", code, owner)
+	}
+	if _, ok := codes[code]; ok {
+		return Errorf("code: %d already exist", code)
+	}
+
+	codes[code] = coder
+
+	return nil
+}
+
+// MustRegister registers a user define error code within the range.
+// It will panic when Register fails.
+func (r CodeRange) MustRegister(coder Coder) {
+	if err := r.Register(coder); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Codes returns the registered codes within the range, sorted by code.
+func (r CodeRange) Codes() []Coder {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	var coders []Coder
+	for code, coder := range codes {
+		if r.Contains(code) {
+			coders = append(coders, coder)
+		}
+	}
+	sort.Slice(coders, func(i, j int) bool { return coders[i].Code() < coders[j].Code() })
+
+	return coders
+}
+
+// declared reports whether the range is declared. codeMux must be held.
+func (r CodeRange) declared() bool {
+	for _, e := range codeRanges {
+		if e == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LookupRange returns the declared range of a service, or of a module if
+// module is not empty.
+func LookupRange(service, module string) (CodeRange, bool) {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	for _, r := range codeRanges {
+		if r.Service == service && r.Module == module {
+			return r, true
+		}
+	}
+
+	return CodeRange{}, false
+}
+
+// RangeOf returns the most specific declared range containing code: the
+// range of a module is preferred over the range of its service.
+func RangeOf(code int) (CodeRange, bool) {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	return rangeOf(code)
+}
+
+// rangeOf is RangeOf. codeMux must be held.
+func rangeOf(code int) (CodeRange, bool) {
+	var (
+		found CodeRange
+		ok    bool
+	)
+	for _, r := range codeRanges {
+		if r.Contains(code) && (!ok || r.Module != "") {
+			found, ok = r, true
+		}
+	}
+
+	return found, ok
+}
+
+// checkUnranged returns an error if code lies within a declared range, whose
+// codes must be registered with CodeRange.Register. codeMux must be held.
+func checkUnranged(code int) error {
+	if owner, ok := rangeOf(code); ok {
+		return Errorf("code: %d belongs to the range %s, register it with the range", code, owner)
+	}
+
+	return nil
+}
+
+// Ranges returns every declared range, sorted by their lower bound.
+func Ranges() []CodeRange {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	ranges := make([]CodeRange, len(codeRanges))
+	copy(ranges, codeRanges)
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].Min < ranges[j].Min })
+
+	return ranges
+}
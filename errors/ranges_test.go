@@ -0,0 +1,150 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRegisterRange(t *testing.T) {
+	tests := []struct {
+		r       CodeRange
+		wantErr bool
+	}{
+		{CodeRange{Service: "iam", Min: 910000, Max: 919999, Owner: "iam-team"}, false},
+		{CodeRange{Service: "iam", Module: "user", Min: 910100, Max: 910199}, false},
+		{CodeRange{Service: "iam", Module: "secret", Min: 910200, Max: 910299}, false},
+		// already declared
+		{CodeRange{Service: "iam", Module: "user", Min: 910300, Max: 910399}, true},
+		// overlaps another module of the service
+		{CodeRange{Service: "iam", Module: "policy", Min: 910150, Max: 910249}, true},
+		// exceeds the service range
+		{CodeRange{Service: "iam", Module: "policy", Min: 919900, Max: 920099}, true},
+		// overlaps another service
+		{CodeRange{Service: "apiserver", Min: 915000, Max: 925000}, true},
+		{CodeRange{Service: "apiserver", Min: 920000, Max: 929999}, false},
+		// service range doesn't contain its modules
+		{CodeRange{Service: "gateway", Module: "route", Min: 930100, Max: 930199}, false},
+		{CodeRange{Service: "gateway", Min: 930200, Max: 939999}, true},
+		// malformed
+		{CodeRange{Min: 940000, Max: 949999}, true},
+		{CodeRange{Service: "pump", Min: 949999, Max: 940000}, true},
+		{CodeRange{Service: "pump", Min: 0, Max: 10}, true},
+	}
+
+	for i, tt := range tests {
+		err := RegisterRange(tt.r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("test %d: RegisterRange(%s): got error %v, want error %v", i+1, tt.r, err, tt.wantErr)
+		}
+	}
+
+	lookups := []struct {
+		code   int
+		want   string
+		wantOK bool
+	}{
+		{910101, "iam/user [910100, 910199]", true},
+		{910500, "iam [910000, 919999]", true},
+		{930150, "gateway/route [930100, 930199]", true},
+		{950000, "", false},
+	}
+
+	for i, tt := range lookups {
+		r, ok := RangeOf(tt.code)
+		if ok != tt.wantOK || (ok && r.String() != tt.want) {
+			t.Errorf("test %d: RangeOf(%d): got %s, %t, want %s, %t", i+1, tt.code, r, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if r, ok := LookupRange("iam", ""); !ok || r.Owner != "iam-team" {
+		t.Errorf("LookupRange(): got %+v, %t, want the iam range", r, ok)
+	}
+}
+
+func TestCodeRangeRegister(t *testing.T) {
+	r := MustRegisterRange(CodeRange{Service: "authz", Module: "policy", Min: 960100, Max: 960199})
+
+	if err := r.Register(defaultCoder{960102, http.StatusNotFound, "Policy not found", ""}); err != nil {
+		t.Errorf("Register(): %v", err)
+	}
+	if err := r.Register(defaultCoder{960101, http.StatusBadRequest, "Policy is invalid", ""}); err != nil {
+		t.Errorf("Register(): %v", err)
+	}
+	if err := r.Register(defaultCoder{960101, http.StatusBadRequest, "Policy is invalid", ""}); err == nil {
+		t.Errorf("Register(): expected error on duplicate registration")
+	}
+	if err := r.Register(defaultCoder{960201, http.StatusBadRequest, "Out of range", ""}); err == nil {
+		t.Errorf("Register(): expected error on code outside of the range")
+	}
+
+	undeclared := CodeRange{Service: "authz", Module: "policy", Min: 960100, Max: 969999}
+	if err := undeclared.Register(defaultCoder{960501, http.StatusBadRequest, "Undeclared", ""}); err == nil {
+		t.Errorf("Register(): expected error on undeclared range")
+	}
+
+	coders := r.Codes()
+	if len(coders) != 2 || coders[0].Code() != 960101 || coders[1].Code() != 960102 {
+		t.Errorf("Codes(): got %v, want [960101 960102]", coders)
+	}
+	if got := ParseCoder(WithCode(960102, "policy p1 not found")); got.HTTPStatus() != http.StatusNotFound {
+		t.Errorf("ParseCoder(): got status %d, want %d", got.HTTPStatus(), http.StatusNotFound)
+	}
+}
+
+func TestRangeOwnership(t *testing.T) {
+	service := MustRegisterRange(CodeRange{Service: "billing", Min: 980000, Max: 989999})
+	module := MustRegisterRange(CodeRange{Service: "billing", Module: "invoice", Min: 980100, Max: 980199})
+
+	if err := service.Register(defaultCoder{980101, http.StatusNotFound, "Invoice not found", ""}); err == nil {
+		t.Errorf("Register(): expected error on a code of a module range")
+	}
+	if err := module.Register(defaultCoder{980101, http.StatusNotFound, "Invoice not found", ""}); err != nil {
+		t.Errorf("Register(): %v", err)
+	}
+	if err := service.Register(defaultCoder{980501, http.StatusNotFound, "Account not found", ""}); err != nil {
+		t.Errorf("Register(): %v", err)
+	}
+
+	for _, register := range []func(Coder){Register, MustRegister} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic on a code of a declared range")
+				}
+			}()
+			register(defaultCoder{980502, http.StatusBadRequest, "Foreign code", ""})
+		}()
+	}
+
+	catalog := `{"codes":[{"code":990801,"message":"Free code"},{"code":980501,"message":"Duplicate code"}]}`
+	if err := LoadCatalog([]byte(catalog), CatalogJSON); err == nil {
+		t.Errorf("LoadCatalog(): expected error on a registered code")
+	}
+	if coder := ParseCoder(WithCode(990801, "free")); coder.Code() == 990801 {
+		t.Errorf("LoadCatalog(): registered a code of a rejected catalog")
+	}
+
+	catalog = `{"codes":[{"code":980102,"message":"Invoice paid"},{"code":980503,"message":"Account closed"}]}`
+	if err := LoadCatalog([]byte(catalog), CatalogJSON); err != nil {
+		t.Errorf("LoadCatalog(): %v", err)
+	}
+	if coders := module.Codes(); len(coders) != 2 || coders[1].Code() != 980102 {
+		t.Errorf("Codes(): got %v, want the invoice codes", coders)
+	}
+
+	// the range would contain codes it doesn't own
+	if err := RegisterRange(CodeRange{Service: "billing", Module: "account", Min: 980500, Max: 980599}); err == nil {
+		t.Errorf("RegisterRange(): expected error on a range containing registered codes")
+	}
+	if err := RegisterRange(CodeRange{Service: "ledger", Min: 990800, Max: 990899}); err != nil {
+		t.Errorf("RegisterRange(): %v", err)
+	}
+	Register(defaultCoder{990901, http.StatusBadRequest, "Global code", ""})
+	if err := RegisterRange(CodeRange{Service: "audit", Min: 990900, Max: 990999}); err == nil {
+		t.Errorf("RegisterRange(): expected error on a range containing a globally registered code")
+	}
+}
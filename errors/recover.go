@@ -0,0 +1,162 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// PanicHandlers is a list of functions which will be invoked with the error
+// of every panic recovered by this package, e.g. to log it.
+var PanicHandlers = []func(error){}
+
+// PanicError is the cause of the errors created from recovered panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+}
+
+// Error implements the error interface.
+func (p *PanicError) Error() string { return fmt.Sprintf("panic: %v", p.Value) }
+
+// Unwrap returns the panic value if it is an error.
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// Recover recovers from a panic and stores it in *errp as an error with the
+// unknown code. The stack of the error is the stack of the panic site.
+// It must be deferred directly:
+//
+//	func do() (err error) {
+//	        defer errors.Recover(&err)
+//	        ...
+//	}
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		setPanicError(errp, fromPanic(r, unknownCoder.Code()))
+	}
+}
+
+// RecoverWithCode is like Recover, but the stored error has the given code.
+// It must be deferred directly.
+func RecoverWithCode(errp *error, code int) {
+	if r := recover(); r != nil {
+		setPanicError(errp, fromPanic(r, code))
+	}
+}
+
+// Safe returns a function which runs fn and converts a panic of fn into an
+// error with the unknown code.
+func Safe(fn func() error) func() error {
+	return func() (err error) {
+		defer Recover(&err)
+
+		return fn()
+	}
+}
+
+// Go runs fn in a new goroutine and returns a channel which receives the
+// result of fn. A panic of fn is received as an error instead of crashing the
+// process.
+func Go(fn func() error) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- Safe(fn)()
+	}()
+
+	return result
+}
+
+// SafeAggregateGoroutines is like AggregateGoroutines, but a panicking function
+// contributes an error to the returned Aggregate instead of crashing the
+// process.
+func SafeAggregateGoroutines(funcs ...func() error) Aggregate {
+	safe := make([]func() error, 0, len(funcs))
+	for _, f := range funcs {
+		safe = append(safe, Safe(f))
+	}
+
+	return AggregateGoroutines(safe...)
+}
+
+// RecoverHandler wraps next and converts its panics into errors with the
+// unknown code. The error is rendered by render, or as plain text with the
+// status and external message of its Coder if render is nil.
+// http.ErrAbortHandler is re-panicked to keep its semantics.
+func RecoverHandler(next http.Handler, render func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	if render == nil {
+		render = func(w http.ResponseWriter, r *http.Request, err error) {
+			coder := ParseCoder(err)
+			http.Error(w, coder.String(), coder.HTTPStatus())
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				render(w, r, fromPanic(v, unknownCoder.Code()))
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func setPanicError(errp *error, err error) {
+	if errp != nil {
+		*errp = err
+	}
+}
+
+// fromPanic converts a recovered panic value into a coded error and passes it
+// to the PanicHandlers. It must be called by the deferred function that
+// recovered the panic.
+func fromPanic(v interface{}, code int) error {
+	err := &withCode{
+		err:   fmt.Errorf("recovered from panic"),
+		code:  code,
+		cause: &PanicError{Value: v},
+		stack: panicCallers(),
+	}
+
+	for _, fn := range PanicHandlers {
+		fn(err)
+	}
+
+	return err
+}
+
+// panicCallers returns the stack of the panic site: the frames of the
+// recovering function and of the runtime panic machinery are dropped.
+func panicCallers() *stack {
+	st := callers()
+	if st == nil {
+		return nil
+	}
+
+	inRuntime := false
+	for i, pc := range *st {
+		name := Frame(pc).name()
+		switch {
+		case name == "runtime.gopanic":
+			inRuntime = true
+		case inRuntime && !strings.HasPrefix(name, "runtime."):
+			trimmed := (*st)[i:]
+			return &trimmed
+		}
+	}
+
+	return st
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func panicking(v interface{}) error {
+	panic(v)
+}
+
+func indexOutOfRange(i int) error {
+	var s []int
+	return New(string(rune(s[i])))
+}
+
+func recovered(fn func() error) (err error) {
+	defer Recover(&err)
+
+	return fn()
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		fn         func() error
+		wantCaller string
+		wantValue  string
+	}{
+		{func() error { return panicking("boom") }, "errors.panicking", "boom"},
+		{func() error { return panicking(io.EOF) }, "errors.panicking", "EOF"},
+		{func() error { return indexOutOfRange(1) }, "errors.indexOutOfRange", "index out of range"},
+	}
+
+	for i, tt := range tests {
+		err := recovered(tt.fn)
+		if !IsCode(err, unknownCoder.Code()) {
+			t.Errorf("test %d: Recover(): expected unknown code in %v", i+1, err)
+		}
+
+		var pe *PanicError
+		if !As(err, &pe) {
+			t.Fatalf("test %d: Recover(): expected *PanicError in chain of %v", i+1, err)
+		}
+		if !strings.Contains(pe.Error(), tt.wantValue) {
+			t.Errorf("test %d: PanicError: got %q, want %q", i+1, pe.Error(), tt.wantValue)
+		}
+
+		st := err.(*withCode).StackTrace()
+		if got := Frame(st[0]).name(); !strings.HasSuffix(got, tt.wantCaller) {
+			t.Errorf("test %d: Recover(): got panic site %s, want %s", i+1, got, tt.wantCaller)
+		}
+	}
+
+	if err := recovered(func() error { return panicking(io.EOF) }); !Is(err, io.EOF) {
+		t.Errorf("Recover(): expected io.EOF in chain of %v", err)
+	}
+	if err := recovered(func() error { return nil }); err != nil {
+		t.Errorf("Recover(): got %v, want nil", err)
+	}
+}
+
+func TestRecoverWithCode(t *testing.T) {
+	err := func() (err error) {
+		defer RecoverWithCode(&err, ErrLoadConfigFailed)
+		return panicking("boom")
+	}()
+
+	if coder := ParseCoder(err); coder.Code() != ErrLoadConfigFailed {
+		t.Errorf("RecoverWithCode(): got code %d, want %d", coder.Code(), ErrLoadConfigFailed)
+	}
+}
+
+func TestGo(t *testing.T) {
+	var handled []error
+	PanicHandlers = append(PanicHandlers, func(err error) { handled = append(handled, err) })
+	defer func() { PanicHandlers = PanicHandlers[:len(PanicHandlers)-1] }()
+
+	if err := <-Go(func() error { return panicking("boom") }); err == nil {
+		t.Errorf("Go(): expected error from panic")
+	}
+	if err := <-Go(func() error { return io.EOF }); err != io.EOF {
+		t.Errorf("Go(): got %v, want %v", err, io.EOF)
+	}
+
+	agg := SafeAggregateGoroutines(
+		func() error { return nil },
+		func() error { return io.EOF },
+		func() error { return panicking("boom") },
+	)
+	if agg == nil || len(agg.Errors()) != 2 {
+		t.Errorf("SafeAggregateGoroutines(): got %v, want 2 errors", agg)
+	}
+
+	if len(handled) != 2 {
+		t.Errorf("PanicHandlers: got %d calls, want 2", len(handled))
+	}
+}
+
+func TestRecoverHandler(t *testing.T) {
+	handler := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), unknownCoder.String()) {
+		t.Errorf("RecoverHandler(): got %d %q", rec.Code, rec.Body.String())
+	}
+
+	abort := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), nil)
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("RecoverHandler(): got panic %v, want http.ErrAbortHandler", r)
+		}
+	}()
+	abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryableCoder is a Coder which classifies its code as retryable or
+// terminal. Coders which don't implement it can be classified with
+// RegisterRetry.
+type RetryableCoder interface {
+	Coder
+
+	// Retryable reports whether the operation failing with the code may
+	// succeed when retried.
+	Retryable() bool
+
+	// RetryAfter returns the suggested delay before retrying, or zero.
+	RetryAfter() time.Duration
+}
+
+// RetryInfo is the retry classification of an error code.
+type RetryInfo struct {
+	// Retryable reports whether the operation failing with the code may
+	// succeed when retried. Codes are terminal by default.
+	Retryable bool
+
+	// RetryAfter is the suggested delay before retrying, or zero.
+	RetryAfter time.Duration
+}
+
+// retryInfos contains the retry classification registered by RegisterRetry.
+// It's guarded by codeMux.
+var retryInfos = map[int]RetryInfo{}
+
+// RegisterRetry registers the retry classification of a code. It overrides
+// the classification of a RetryableCoder.
+func RegisterRetry(code int, info RetryInfo) {
+	codeMux.Lock()
+	defer codeMux.Unlock()
+
+	retryInfos[code] = info
+}
+
+// RetryInfoOf returns the retry classification of the code of err, as found
+// by ParseCoder.
+func RetryInfoOf(err error) RetryInfo {
+	if err == nil {
+		return RetryInfo{}
+	}
+
+	coder := ParseCoder(err)
+
+	codeMux.Lock()
+	info, ok := retryInfos[coder.Code()]
+	codeMux.Unlock()
+	if ok {
+		return info
+	}
+
+	if rc, ok := coder.(RetryableCoder); ok {
+		return RetryInfo{Retryable: rc.Retryable(), RetryAfter: rc.RetryAfter()}
+	}
+
+	return RetryInfo{}
+}
+
+// IsRetryable reports whether the code of err is classified as retryable.
+func IsRetryable(err error) bool {
+	return RetryInfoOf(err).Retryable
+}
+
+// RetryPolicy controls the attempts made by Retry.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Zero means 3.
+	MaxAttempts int
+
+	// InitialInterval is the delay before the second attempt. Zero means
+	// 100ms.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between attempts. Zero means 10s.
+	MaxInterval time.Duration
+
+	// Multiplier is the factor the delay grows by after each attempt.
+	// Zero means 2.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to the given fraction of it, e.g.
+	// 0.2 for ±20%. Zero disables jitter. It's clamped to [0, 1], so
+	// the delay is never negative.
+	Jitter float64
+
+	// Retryable reports whether a failed attempt is retried. Nil means
+	// IsRetryable.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy is a policy making up to 3 attempts with an exponential
+// backoff starting at 100ms and a jitter of 20%.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	InitialInterval: 100 * time.Millisecond,
+	MaxInterval:     10 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+}
+
+// Retry calls fn until it succeeds, fails with a terminal error, the attempts
+// of policy are exhausted or ctx is done. The delay between attempts grows
+// exponentially, and is at least the RetryAfter of the failed attempt's code.
+//
+// Retry returns nil if an attempt succeeded. Otherwise it returns an Aggregate
+// of the error of every attempt, each prefixed with its attempt number,
+// followed by the error of ctx if it was done while waiting.
+func Retry(ctx context.Context, fn func() error, policy RetryPolicy) error {
+	policy.setDefaults()
+
+	var errs []error
+	interval := policy.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, WithMessagef(err, "attempt %d: %v", attempt, err))
+
+		if attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+			return NewAggregate(errs)
+		}
+
+		delay := policy.jitter(interval)
+		if after := RetryInfoOf(err).RetryAfter; after > delay {
+			delay = after
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return NewAggregate(append(errs, ctx.Err()))
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+func (p *RetryPolicy) setDefaults() {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = DefaultRetryPolicy.InitialInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultRetryPolicy.MaxInterval
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	}
+	if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+}
+
+// jitter randomizes d by up to the Jitter fraction of it.
+func (p *RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+
+	return time.Duration(float64(d) * (1 + p.Jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	ErrUnavailable = 970001 + iota
+	ErrThrottled
+	ErrConflict
+)
+
+type retryCoder struct {
+	defaultCoder
+	after time.Duration
+}
+
+func (coder retryCoder) Retryable() bool           { return true }
+func (coder retryCoder) RetryAfter() time.Duration { return coder.after }
+
+func init() {
+	Register(defaultCoder{ErrUnavailable, http.StatusServiceUnavailable, "Service unavailable", ""})
+	Register(retryCoder{defaultCoder{ErrThrottled, http.StatusTooManyRequests, "Too many requests", ""}, 5 * time.Millisecond})
+	Register(defaultCoder{ErrConflict, http.StatusConflict, "Conflict", ""})
+	RegisterRetry(ErrUnavailable, RetryInfo{Retryable: true})
+}
+
+func TestRetryInfoOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want RetryInfo
+	}{
+		{nil, RetryInfo{}},
+		{io.EOF, RetryInfo{}},
+		{WithCode(ErrUnavailable, "down"), RetryInfo{Retryable: true}},
+		{Wrap(WithCode(ErrThrottled, "slow down"), "call"), RetryInfo{Retryable: true, RetryAfter: 5 * time.Millisecond}},
+		{WithCode(ErrConflict, "conflict"), RetryInfo{}},
+	}
+
+	for i, tt := range tests {
+		if got := RetryInfoOf(tt.err); got != tt.want {
+			t.Errorf("test %d: RetryInfoOf(%v): got %+v, want %+v", i+1, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, InitialInterval: time.Millisecond, Jitter: 0.5}
+
+	tests := []struct {
+		errs     []error
+		attempts int
+		want     string
+	}{
+		{nil, 1, ""},
+		{[]error{WithCode(ErrUnavailable, "down")}, 2, ""},
+		{[]error{WithCode(ErrConflict, "conflict")}, 1, "attempt 1: Conflict"},
+		{
+			[]error{WithCode(ErrUnavailable, "down"), WithCode(ErrThrottled, "slow"), WithCode(ErrConflict, "conflict")},
+			3,
+			"[attempt 1: Service unavailable, attempt 2: Too many requests, attempt 3: Conflict]",
+		},
+		{
+			[]error{
+				WithCode(ErrUnavailable, "down"), WithCode(ErrUnavailable, "down"),
+				WithCode(ErrUnavailable, "down"), WithCode(ErrUnavailable, "down"),
+			},
+			4,
+			"[attempt 1: Service unavailable, attempt 2: Service unavailable, attempt 3: Service unavailable, attempt 4: Service unavailable]",
+		},
+	}
+
+	for i, tt := range tests {
+		attempts := 0
+		err := Retry(context.Background(), func() error {
+			attempts++
+			if attempts <= len(tt.errs) {
+				return tt.errs[attempts-1]
+			}
+			return nil
+		}, policy)
+
+		if attempts != tt.attempts {
+			t.Errorf("test %d: Retry(): got %d attempts, want %d", i+1, attempts, tt.attempts)
+		}
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("test %d: Retry(): got %q, want %q", i+1, got, tt.want)
+		}
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	err := Retry(ctx, func() error {
+		attempts++
+		cancel()
+		return WithCode(ErrUnavailable, "down")
+	}, RetryPolicy{InitialInterval: time.Hour})
+
+	if attempts != 1 {
+		t.Errorf("Retry(): got %d attempts, want 1", attempts)
+	}
+	if !Is(err, context.Canceled) || !IsCode(err, ErrUnavailable) {
+		t.Errorf("Retry(): got %v, want the attempt and the context error", err)
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	attempts := 0
+	err := Retry(context.Background(), func() error {
+		attempts++
+		return io.EOF
+	}, RetryPolicy{MaxAttempts: 2, InitialInterval: time.Millisecond, Retryable: func(err error) bool {
+		return Is(err, io.EOF)
+	}})
+
+	if attempts != 2 || !Is(err, io.EOF) {
+		t.Errorf("Retry(): got %d attempts and %v, want 2 attempts and EOF", attempts, err)
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	tests := []struct {
+		jitter float64
+		want   float64
+	}{
+		{-0.5, 0},
+		{0, 0},
+		{0.2, 0.2},
+		{1, 1},
+		{3, 1},
+	}
+
+	for _, tt := range tests {
+		policy := RetryPolicy{Jitter: tt.jitter}
+		policy.setDefaults()
+		if policy.Jitter != tt.want {
+			t.Errorf("setDefaults(): got jitter %v for %v, want %v", policy.Jitter, tt.jitter, tt.want)
+		}
+
+		for i := 0; i < 100; i++ {
+			if d := policy.jitter(time.Second); d < 0 || d > 2*time.Second {
+				t.Fatalf("jitter(): got %v with jitter %v, want within [0, 2s]", d, tt.jitter)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package errors
+
+import (
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+var (
+	_ slog.LogValuer = &fundamental{}
+	_ slog.LogValuer = &withStack{}
+	_ slog.LogValuer = &withMessage{}
+	_ slog.LogValuer = &withCode{}
+	_ slog.LogValuer = &withDetails{}
+	_ slog.LogValuer = aggregate{}
+)
+
+// LogValue implements slog.LogValuer.
+func (f *fundamental) LogValue() slog.Value { return newErrorLog(f).logValue() }
+
+// LogValue implements slog.LogValuer.
+func (w *withStack) LogValue() slog.Value { return newErrorLog(w).logValue() }
+
+// LogValue implements slog.LogValuer.
+func (w *withMessage) LogValue() slog.Value { return newErrorLog(w).logValue() }
+
+// LogValue implements slog.LogValuer.
+func (w *withCode) LogValue() slog.Value { return newErrorLog(w).logValue() }
+
+// LogValue implements slog.LogValuer.
+func (w *withDetails) LogValue() slog.Value { return newErrorLog(w).logValue() }
+
+// LogValue implements slog.LogValuer.
+func (agg aggregate) LogValue() slog.Value { return newErrorLog(agg).logValue() }
+
+// logValue converts the errorLog into nested slog attributes.
+func (entry *errorLog) logValue() slog.Value {
+	attrs := []slog.Attr{slog.String("error", entry.Error)}
+	if entry.Message != "" {
+		attrs = append(attrs, slog.String("message", entry.Message))
+	}
+	if entry.Code != 0 {
+		attrs = append(attrs, slog.Int("code", entry.Code))
+	}
+	if entry.HTTPStatus != 0 {
+		attrs = append(attrs, slog.Int("httpStatus", entry.HTTPStatus))
+	}
+	if entry.Caller != "" {
+		attrs = append(attrs, slog.String("caller", entry.Caller))
+	}
+	if len(entry.Stack) > 0 {
+		attrs = append(attrs, slog.Any("stack", entry.Stack))
+	}
+	if len(entry.Details) > 0 {
+		keys := make([]string, 0, len(entry.Details))
+		for k := range entry.Details {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		details := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			details = append(details, slog.Any(k, entry.Details[k]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "details", Value: slog.GroupValue(details...)})
+	}
+	if entry.Cause != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: entry.Cause.logValue()})
+	}
+	if len(entry.Errors) > 0 {
+		nested := make([]slog.Attr, 0, len(entry.Errors))
+		for i, e := range entry.Errors {
+			nested = append(nested, slog.Attr{Key: strconv.Itoa(i), Value: e.logValue()})
+		}
+		attrs = append(attrs, slog.Attr{Key: "errors", Value: slog.GroupValue(nested...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logger.Error("load failed", "err", Wrap(decodeConfig(), "start server"))
+
+	var record struct {
+		Err struct {
+			Error      string   `json:"error"`
+			Code       int      `json:"code"`
+			HTTPStatus int      `json:"httpStatus"`
+			Caller     string   `json:"caller"`
+			Stack      []string `json:"stack"`
+			Cause      struct {
+				Code  int `json:"code"`
+				Cause struct {
+					Code  int `json:"code"`
+					Cause struct {
+						Error string `json:"error"`
+					} `json:"cause"`
+				} `json:"cause"`
+			} `json:"cause"`
+		} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+
+	got := record.Err
+	if got.Error != "start server" || got.Code != ErrInvalidJSON || got.HTTPStatus != 500 ||
+		got.Caller == "" || len(got.Stack) == 0 {
+		t.Errorf("LogValue(): got %+v", got)
+	}
+	if got.Cause.Code != ErrInvalidJSON || got.Cause.Cause.Code != ErrEOF ||
+		got.Cause.Cause.Cause.Error != "read: end of input" {
+		t.Errorf("LogValue(): unexpected cause chain in %s", buf.String())
+	}
+}
@@ -144,12 +144,30 @@ func (st StackTrace) formatSlice(s fmt.State, verb rune) {
 type stack []uintptr
 
 func (s *stack) Format(st fmt.State, verb rune) {
+	s.format(st, verb, nil)
+}
+
+// format formats the stack like Format. If the stack policy dedupes stacks,
+// the outermost frames s shares with the stack of its cause are omitted.
+func (s *stack) format(st fmt.State, verb rune, cause *stack) {
+	if s == nil {
+		return
+	}
+
 	switch verb {
 	case 'v':
 		switch {
 		case st.Flag('+'):
-			for _, pc := range *s {
+			policy := currentStackPolicy()
+			pcs := *s
+			if policy.Dedupe && cause != nil {
+				pcs = pcs[:len(pcs)-commonSuffix(pcs, *cause)]
+			}
+			for _, pc := range pcs {
 				f := Frame(pc)
+				if policy.trimmed(f) {
+					continue
+				}
 				fmt.Fprintf(st, "\n%+v", f)
 			}
 		}
@@ -157,6 +175,10 @@ func (s *stack) Format(st fmt.State, verb rune) {
 }
 
 func (s *stack) StackTrace() StackTrace {
+	if s == nil {
+		return nil
+	}
+
 	f := make([]Frame, len(*s))
 	for i := 0; i < len(f); i++ {
 		f[i] = Frame((*s)[i])
@@ -164,14 +186,62 @@ func (s *stack) StackTrace() StackTrace {
 	return f
 }
 
+// callers returns the stack of the caller of the function calling callers,
+// or nil if the stack policy doesn't capture it.
 func callers() *stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(3, pcs[:])
+	return captureStack(nil)
+}
+
+// codeCallers is like callers, for an error with the given code.
+func codeCallers(code int) *stack {
+	return captureStack(&code)
+}
+
+func captureStack(code *int) *stack {
+	policy := currentStackPolicy()
+	if !policy.capture(code) {
+		return nil
+	}
+
+	pcs := make([]uintptr, policy.depth())
+	n := runtime.Callers(4, pcs)
 	var st stack = pcs[0:n]
 	return &st
 }
 
+// commonSuffix returns the number of outermost frames a and b have in common.
+func commonSuffix(a, b stack) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+
+	return n
+}
+
+// stackOf returns the stack of the first error in err's chain that has one.
+func stackOf(err error) *stack {
+	for err != nil {
+		switch e := err.(type) {
+		case *fundamental:
+			return e.stack
+		case *withStack:
+			return e.stack
+		case *withCode:
+			if e.stack != nil {
+				return e.stack
+			}
+			err = e.cause
+		case interface{ Cause() error }:
+			err = e.Cause()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // funcname removes the path prefix component of a function's name reported by func.Name().
 func funcname(name string) string {
 	i := strings.LastIndex(name, "/")
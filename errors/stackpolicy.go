@@ -0,0 +1,107 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// defaultStackDepth is the number of frames captured by default.
+const defaultStackDepth = 32
+
+// StackPolicy controls how stack traces are captured by New, Errorf, Wrap,
+// WithStack, WithCode and friends, and how they are formatted.
+// The zero value captures and prints the full stack of every error.
+type StackPolicy struct {
+	// Disabled turns stack capture off. Errors created while capture is off
+	// carry no stack and print no frames.
+	Disabled bool
+
+	// Depth limits the number of captured frames. Zero means 32.
+	Depth int
+
+	// CaptureCode, if set, reports whether the stack of an error with the
+	// given code is captured. It is not consulted for errors without code.
+	CaptureCode func(code int) bool
+
+	// SampleEvery captures the stack of only one out of every SampleEvery
+	// errors. Zero and one capture every stack.
+	SampleEvery uint32
+
+	// TrimRuntime omits the frames of the Go runtime and testing packages
+	// when formatting with %+v.
+	TrimRuntime bool
+
+	// TrimVendor omits the frames of vendored packages when formatting
+	// with %+v.
+	TrimVendor bool
+
+	// Dedupe omits the frames a wrapping error shares with the stack of its
+	// cause when formatting a chain with %+v.
+	Dedupe bool
+}
+
+var (
+	stackPolicy  atomic.Value
+	stackSamples uint32
+)
+
+func init() {
+	stackPolicy.Store(&StackPolicy{})
+}
+
+// SetStackPolicy replaces the package-level stack policy.
+func SetStackPolicy(policy StackPolicy) {
+	stackPolicy.Store(&policy)
+}
+
+// GetStackPolicy returns the package-level stack policy.
+func GetStackPolicy() StackPolicy {
+	return *currentStackPolicy()
+}
+
+func currentStackPolicy() *StackPolicy {
+	return stackPolicy.Load().(*StackPolicy)
+}
+
+// capture reports whether a stack should be captured for an error with the
+// given code, or without code if code is nil.
+func (p *StackPolicy) capture(code *int) bool {
+	if p.Disabled {
+		return false
+	}
+	if code != nil && p.CaptureCode != nil && !p.CaptureCode(*code) {
+		return false
+	}
+	if p.SampleEvery > 1 && atomic.AddUint32(&stackSamples, 1)%p.SampleEvery != 0 {
+		return false
+	}
+
+	return true
+}
+
+func (p *StackPolicy) depth() int {
+	if p.Depth <= 0 {
+		return defaultStackDepth
+	}
+
+	return p.Depth
+}
+
+// trimmed reports whether the frame is omitted when formatting.
+func (p *StackPolicy) trimmed(f Frame) bool {
+	if p.TrimRuntime {
+		name := f.name()
+		if strings.HasPrefix(name, "runtime.") || strings.HasPrefix(name, "testing.") {
+			return true
+		}
+	}
+	if p.TrimVendor && strings.Contains(f.file(), "/vendor/") {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type stackTracer interface {
+	StackTrace() StackTrace
+}
+
+func stackDepth(err error) int {
+	return len(err.(stackTracer).StackTrace())
+}
+
+func TestStackPolicyCapture(t *testing.T) {
+	defer SetStackPolicy(StackPolicy{})
+
+	SetStackPolicy(StackPolicy{Disabled: true})
+	for _, err := range []error{New("new"), Wrap(New("new"), "wrap"), WithCode(ErrEOF, "code")} {
+		if got := stackDepth(err); got != 0 {
+			t.Errorf("Disabled: got %d frames for %v", got, err)
+		}
+		if got := fmt.Sprintf("%+v", err); strings.Contains(got, "stackpolicy_test.go") {
+			t.Errorf("Disabled: got frames in %q", got)
+		}
+		if got := fmt.Sprintf("%-v", err); got == "" {
+			t.Errorf("Disabled: got empty %%-v output")
+		}
+	}
+
+	SetStackPolicy(StackPolicy{Depth: 1})
+	if got := stackDepth(New("new")); got != 1 {
+		t.Errorf("Depth: got %d frames, want 1", got)
+	}
+
+	SetStackPolicy(StackPolicy{CaptureCode: func(code int) bool { return code == ErrEOF }})
+	if got := stackDepth(WithCode(ErrEOF, "eof")); got == 0 {
+		t.Errorf("CaptureCode: expected stack for code %d", ErrEOF)
+	}
+	if got := stackDepth(WithCode(ErrInvalidJSON, "json")); got != 0 {
+		t.Errorf("CaptureCode: got %d frames for code %d", got, ErrInvalidJSON)
+	}
+	if got := stackDepth(New("new")); got == 0 {
+		t.Errorf("CaptureCode: expected stack for error without code")
+	}
+
+	SetStackPolicy(StackPolicy{SampleEvery: 4})
+	captured := 0
+	for i := 0; i < 100; i++ {
+		if stackDepth(New("new")) > 0 {
+			captured++
+		}
+	}
+	if captured != 25 {
+		t.Errorf("SampleEvery: captured %d of 100 stacks, want 25", captured)
+	}
+
+	if got := GetStackPolicy(); got.SampleEvery != 4 {
+		t.Errorf("GetStackPolicy(): got %+v", got)
+	}
+}
+
+func TestStackPolicyFormat(t *testing.T) {
+	defer SetStackPolicy(StackPolicy{})
+
+	err := Wrap(New("error"), "wrapped")
+	full := fmt.Sprintf("%+v", err)
+
+	SetStackPolicy(StackPolicy{TrimRuntime: true})
+	trimmed := fmt.Sprintf("%+v", err)
+	if strings.Contains(trimmed, "testing.tRunner") || strings.Contains(trimmed, "runtime.goexit") {
+		t.Errorf("TrimRuntime: got runtime frames in\n%s", trimmed)
+	}
+	if !strings.Contains(full, "testing.tRunner") {
+		t.Errorf("default policy: expected runtime frames in\n%s", full)
+	}
+
+	SetStackPolicy(StackPolicy{Dedupe: true})
+	deduped := fmt.Sprintf("%+v", err)
+	if got := strings.Count(deduped, "testing.tRunner"); got != 1 {
+		t.Errorf("Dedupe: got %d tRunner frames, want 1 in\n%s", got, deduped)
+	}
+	if got := strings.Count(deduped, "TestStackPolicyFormat"); got != 2 {
+		t.Errorf("Dedupe: got %d test frames, want 2 in\n%s", got, deduped)
+	}
+}
@@ -120,7 +120,10 @@ type Requirement struct {
 // (2) If the operator is In or NotIn, the values set must be non-empty.
 // (3) If the operator is Equals, DoubleEquals, or NotEquals, the values set must contain one value.
 // (4) If the operator is Exists or DoesNotExist, the value set must be empty.
-// (5) If the operator is Gt or Lt, the values set must contain only one value, which will be interpreted as an integer.
+// (5) If the operator is GreaterThan, GreaterThanOrEquals, LessThan or LessThanOrEquals, the values set must
+//
+//	contain only one value, which will be interpreted as an integer.
+//
 // (6) The key is invalid due to its length, or sequence
 //
 //	of characters. See validateLabelKey for more details.
@@ -143,14 +146,12 @@ func NewRequirement(key string, op selection.Operator, vals []string) (*Requirem
 		if len(vals) != 0 {
 			return nil, fmt.Errorf("values set must be empty for exists and does not exist")
 		}
-	case selection.GreaterThan, selection.LessThan:
+	case selection.GreaterThan, selection.GreaterThanOrEquals, selection.LessThan, selection.LessThanOrEquals:
 		if len(vals) != 1 {
-			return nil, fmt.Errorf("for 'Gt', 'Lt' operators, exactly one value is required")
+			return nil, fmt.Errorf("for '%s' operator, exactly one value is required, found %d", op, len(vals))
 		}
-		for i := range vals {
-			if _, err := strconv.ParseInt(vals[i], 10, 64); err != nil {
-				return nil, fmt.Errorf("for 'Gt', 'Lt' operators, the value must be an integer")
-			}
+		if _, err := strconv.ParseInt(vals[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("for '%s' operator, the value must be an integer, found %q", op, vals[0])
 		}
 	default:
 		return nil, fmt.Errorf("operator '%v' is not recognized", op)
@@ -188,9 +189,10 @@ func (r *Requirement) hasValue(value string) bool {
 //
 //	Requirement's key.
 //
-// (5) The operator is GreaterThanOperator or LessThanOperator, and Labels has
+// (5) The operator is GreaterThan, GreaterThanOrEquals, LessThan or LessThanOrEquals,
 //
-//	the Requirement's key and the corresponding value satisfies mathematical inequality.
+//	and Labels has the Requirement's key and the corresponding value is an integer
+//	which satisfies the mathematical inequality.
 func (r *Requirement) Matches(ls Labels) bool {
 	switch r.operator {
 	case selection.In, selection.Equals, selection.DoubleEquals:
@@ -207,7 +209,7 @@ func (r *Requirement) Matches(ls Labels) bool {
 		return ls.Has(r.key)
 	case selection.DoesNotExist:
 		return !ls.Has(r.key)
-	case selection.GreaterThan, selection.LessThan:
+	case selection.GreaterThan, selection.GreaterThanOrEquals, selection.LessThan, selection.LessThanOrEquals:
 		if !ls.Has(r.key) {
 			return false
 		}
@@ -224,17 +226,23 @@ func (r *Requirement) Matches(ls Labels) bool {
 			return false
 		}
 
-		var rValue int64
-		for i := range r.strValues {
-			rValue, err = strconv.ParseInt(r.strValues[i], 10, 64)
-			if err != nil {
-				// klog.V(10).Infof("ParseInt failed for value %+v in requirement %#v, for 'Gt', 'Lt' operators, the
-				// value must be an integer", r.strValues[i], r)
-				return false
-			}
+		rValue, err := strconv.ParseInt(r.strValues[0], 10, 64)
+		if err != nil {
+			// klog.V(10).Infof("ParseInt failed for value %+v in requirement %#v, for 'Gt', 'Lt' operators, the
+			// value must be an integer", r.strValues[0], r)
+			return false
+		}
+
+		switch r.operator {
+		case selection.GreaterThan:
+			return lsValue > rValue
+		case selection.GreaterThanOrEquals:
+			return lsValue >= rValue
+		case selection.LessThan:
+			return lsValue < rValue
+		default:
+			return lsValue <= rValue
 		}
-		return (r.operator == selection.GreaterThan && lsValue > rValue) ||
-			(r.operator == selection.LessThan && lsValue < rValue)
 	default:
 		return false
 	}
@@ -290,8 +298,12 @@ func (r *Requirement) String() string {
 		buffer.WriteString(" notin ")
 	case selection.GreaterThan:
 		buffer.WriteString(">")
+	case selection.GreaterThanOrEquals:
+		buffer.WriteString(">=")
 	case selection.LessThan:
 		buffer.WriteString("<")
+	case selection.LessThanOrEquals:
+		buffer.WriteString("<=")
 	case selection.Exists, selection.DoesNotExist:
 		return buffer.String()
 	}
@@ -412,6 +424,13 @@ const (
 	NotInToken
 	// OpenParToken represents open parenthesis.
 	OpenParToken
+
+	// The tokens below are appended to keep the values of the others stable.
+
+	// GreaterThanOrEqualsToken represents greater than or equals.
+	GreaterThanOrEqualsToken
+	// LessThanOrEqualsToken represents less than or equals.
+	LessThanOrEqualsToken
 )
 
 // string2token contains the mapping between lexer Token and token literal
@@ -423,17 +442,21 @@ var string2token = map[string]Token{
 	"==":    DoubleEqualsToken,
 	"=":     EqualsToken,
 	">":     GreaterThanToken,
+	">=":    GreaterThanOrEqualsToken,
 	"in":    InToken,
 	"<":     LessThanToken,
+	"<=":    LessThanOrEqualsToken,
 	"!=":    NotEqualsToken,
 	"notin": NotInToken,
 	"(":     OpenParToken,
 }
 
-// ScannedItem contains the Token and the literal produced by the lexer.
+// ScannedItem contains the Token and the literal produced by the lexer,
+// and the position of the token in the input string.
 type ScannedItem struct {
 	tok     Token
 	literal string
+	pos     int
 }
 
 // isWhitespace returns true if the rune is a space, tab, or newline.
@@ -457,6 +480,8 @@ type Lexer struct {
 	s string
 	// pos is the position currently tokenized
 	pos int
+	// start is the position of the last token returned by Lex
+	start int
 }
 
 // read return the character currently lexed
@@ -498,7 +523,7 @@ IdentifierLoop:
 }
 
 // scanSpecialSymbol scans string starting with special symbol.
-// special symbol identify non literal operators. "!=", "==", "=".
+// special symbol identify non literal operators. "!=", "==", "=", ">=", "<=".
 func (l *Lexer) scanSpecialSymbol() (Token, string) {
 	lastScannedItem := ScannedItem{}
 	var buffer []byte
@@ -540,7 +565,12 @@ func (l *Lexer) skipWhiteSpaces(ch byte) byte {
 // Lex returns a pair of Token and the literal
 // literal is meaningfull only for IdentifierToken token.
 func (l *Lexer) Lex() (tok Token, lit string) {
-	switch ch := l.skipWhiteSpaces(l.read()); {
+	ch := l.skipWhiteSpaces(l.read())
+	l.start = l.pos
+	if ch != 0 {
+		l.start--
+	}
+	switch {
 	case ch == 0:
 		return EndOfStringToken, ""
 	case isSpecialSymbol(ch):
@@ -602,13 +632,31 @@ func (p *Parser) consume(context ParserContext) (Token, string) {
 func (p *Parser) scan() {
 	for {
 		token, literal := p.l.Lex()
-		p.scannedItems = append(p.scannedItems, ScannedItem{token, literal})
+		p.scannedItems = append(p.scannedItems, ScannedItem{tok: token, literal: literal, pos: p.l.start})
 		if token == EndOfStringToken {
 			break
 		}
 	}
 }
 
+// ParseError is the error returned by Parse for an invalid selector.
+type ParseError struct {
+	// Position is the byte offset of the problem in the selector.
+	Position int
+	// Message describes the problem.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s (at position %d)", e.Message, e.Position)
+}
+
+// errorf returns a ParseError at the position of the i-th scanned item.
+func (p *Parser) errorf(i int, format string, args ...interface{}) error {
+	return &ParseError{Position: p.scannedItems[i].pos, Message: fmt.Sprintf(format, args...)}
+}
+
 // parse runs the left recursive descending algorithm
 // on input string. It returns a list of Requirement objects.
 func (p *Parser) parse() (internalSelector, error) {
@@ -621,6 +669,9 @@ func (p *Parser) parse() (internalSelector, error) {
 		case IdentifierToken, DoesNotExistToken:
 			r, err := p.parseRequirement()
 			if err != nil {
+				if pe, ok := err.(*ParseError); ok {
+					return nil, &ParseError{Position: pe.Position, Message: "unable to parse requirement: " + pe.Message}
+				}
 				return nil, fmt.Errorf("unable to parse requirement: %v", err)
 			}
 			requirements = append(requirements, *r)
@@ -631,15 +682,15 @@ func (p *Parser) parse() (internalSelector, error) {
 			case CommaToken:
 				t2, l2 := p.lookahead(Values)
 				if t2 != IdentifierToken && t2 != DoesNotExistToken {
-					return nil, fmt.Errorf("found '%s', expected: identifier after ','", l2)
+					return nil, p.errorf(p.position, "found '%s', expected: identifier after ','", l2)
 				}
 			default:
-				return nil, fmt.Errorf("found '%s', expected: ',' or 'end of string'", l)
+				return nil, p.errorf(p.position-1, "found '%s', expected: ',' or 'end of string'", l)
 			}
 		case EndOfStringToken:
 			return requirements, nil
 		default:
-			return nil, fmt.Errorf("found '%s', expected: !, identifier, or 'end of string'", lit)
+			return nil, p.errorf(p.position, "found '%s', expected: !, identifier, or 'end of string'", lit)
 		}
 	}
 }
@@ -656,17 +707,33 @@ func (p *Parser) parseRequirement() (*Requirement, error) {
 	if err != nil {
 		return nil, err
 	}
+	start := p.position
 	var values sets.String
 	switch operator {
 	case selection.In, selection.NotIn:
 		values, err = p.parseValues()
-	case selection.Equals, selection.DoubleEquals, selection.NotEquals, selection.GreaterThan, selection.LessThan:
+	case selection.Equals, selection.DoubleEquals, selection.NotEquals:
 		values, err = p.parseExactValue()
+	case selection.GreaterThan, selection.GreaterThanOrEquals, selection.LessThan, selection.LessThanOrEquals:
+		values, err = p.parseIntegerValue(operator)
 	}
 	if err != nil {
 		return nil, err
 	}
-	return NewRequirement(key, operator, values.List())
+	// validate the values here to report the position of the invalid one
+	for i := start; i < p.position; i++ {
+		switch item := p.scannedItems[i]; item.tok {
+		case IdentifierToken, InToken, NotInToken:
+			if err := validateLabelValue(key, item.literal); err != nil {
+				return nil, p.errorf(i, "%v", err)
+			}
+		}
+	}
+	r, err := NewRequirement(key, operator, values.List())
+	if err != nil {
+		return nil, p.errorf(start, "%v", err)
+	}
+	return r, nil
 }
 
 // parseKeyAndInferOperator parse literals.
@@ -680,11 +747,11 @@ func (p *Parser) parseKeyAndInferOperator() (string, selection.Operator, error)
 		tok, literal = p.consume(Values)
 	}
 	if tok != IdentifierToken {
-		err := fmt.Errorf("found '%s', expected: identifier", literal)
+		err := p.errorf(p.position-1, "found '%s', expected: identifier", literal)
 		return "", "", err
 	}
 	if err := validateLabelKey(literal); err != nil {
-		return "", "", err
+		return "", "", p.errorf(p.position-1, "%v", err)
 	}
 	if t, _ := p.lookahead(Values); t == EndOfStringToken || t == CommaToken {
 		if operator != selection.DoesNotExist {
@@ -708,14 +775,19 @@ func (p *Parser) parseOperator() (op selection.Operator, err error) {
 		op = selection.DoubleEquals
 	case GreaterThanToken:
 		op = selection.GreaterThan
+	case GreaterThanOrEqualsToken:
+		op = selection.GreaterThanOrEquals
 	case LessThanToken:
 		op = selection.LessThan
+	case LessThanOrEqualsToken:
+		op = selection.LessThanOrEquals
 	case NotInToken:
 		op = selection.NotIn
 	case NotEqualsToken:
 		op = selection.NotEquals
 	default:
-		return "", fmt.Errorf("found '%s', expected: '=', '!=', '==', 'in', notin'", lit)
+		return "", p.errorf(p.position-1,
+			"found '%s', expected: '=', '!=', '==', 'in', 'notin', '>', '>=', '<', '<='", lit)
 	}
 	return op, nil
 }
@@ -724,7 +796,7 @@ func (p *Parser) parseOperator() (op selection.Operator, err error) {
 func (p *Parser) parseValues() (sets.String, error) {
 	tok, lit := p.consume(Values)
 	if tok != OpenParToken {
-		return nil, fmt.Errorf("found '%s' expected: '('", lit)
+		return nil, p.errorf(p.position-1, "found '%s' expected: '('", lit)
 	}
 	tok, lit = p.lookahead(Values)
 	switch tok {
@@ -734,14 +806,14 @@ func (p *Parser) parseValues() (sets.String, error) {
 			return s, err
 		}
 		if tok, _ = p.consume(Values); tok != ClosedParToken {
-			return nil, fmt.Errorf("found '%s', expected: ')'", lit)
+			return nil, p.errorf(p.position-1, "found '%s', expected: ')'", lit)
 		}
 		return s, nil
 	case ClosedParToken: // handles "()"
 		p.consume(Values)
 		return sets.NewString(""), nil
 	default:
-		return nil, fmt.Errorf("found '%s', expected: ',', ')' or identifier", lit)
+		return nil, p.errorf(p.position, "found '%s', expected: ',', ')' or identifier", lit)
 	}
 }
 
@@ -761,7 +833,7 @@ func (p *Parser) parseIdentifiersList() (sets.String, error) {
 			case ClosedParToken:
 				return s, nil
 			default:
-				return nil, fmt.Errorf("found '%s', expected: ',' or ')'", lit2)
+				return nil, p.errorf(p.position, "found '%s', expected: ',' or ')'", lit2)
 			}
 		case CommaToken: // handled here since we can have "(,"
 			if s.Len() == 0 {
@@ -777,7 +849,7 @@ func (p *Parser) parseIdentifiersList() (sets.String, error) {
 				s.Insert("") // to handle ,, Double "" removed by StringSet
 			}
 		default: // it can be operator
-			return s, fmt.Errorf("found '%s', expected: ',', or identifier", lit)
+			return s, p.errorf(p.position-1, "found '%s', expected: ',', or identifier", lit)
 		}
 	}
 }
@@ -795,7 +867,27 @@ func (p *Parser) parseExactValue() (sets.String, error) {
 		s.Insert(lit)
 		return s, nil
 	}
-	return nil, fmt.Errorf("found '%s', expected: identifier", lit)
+	return nil, p.errorf(p.position-1, "found '%s', expected: identifier", lit)
+}
+
+// parseIntegerValue parses the only value of the numeric comparison op,
+// which must be an integer.
+func (p *Parser) parseIntegerValue(op selection.Operator) (sets.String, error) {
+	tok, lit := p.lookahead(Values)
+	switch tok {
+	case IdentifierToken:
+		if _, err := strconv.ParseInt(lit, 10, 64); err != nil {
+			return nil, p.errorf(p.position, "for '%s' operator, the value must be an integer, found '%s'", op, lit)
+		}
+		p.consume(Values)
+		return sets.NewString(lit), nil
+	case OpenParToken:
+		return nil, p.errorf(p.position, "for '%s' operator, exactly one value is required, found a set of values", op)
+	case EndOfStringToken, CommaToken:
+		return nil, p.errorf(p.position, "for '%s' operator, a value is required", op)
+	default:
+		return nil, p.errorf(p.position, "found '%s', expected: integer", lit)
+	}
 }
 
 // Parse takes a string representing a selector and returns a selector
@@ -804,7 +896,7 @@ func (p *Parser) parseExactValue() (sets.String, error) {
 // The input will cause an error if it does not follow this form:
 //
 //	<selector-syntax>         ::= <requirement> | <requirement> "," <selector-syntax>
-//	<requirement>             ::= [!] KEY [ <set-based-restriction> | <exact-match-restriction> ]
+//	<requirement>             ::= [!] KEY [ <set-based-restriction> | <exact-match-restriction> | <numeric-restriction> ]
 //	<set-based-restriction>   ::= "" | <inclusion-exclusion> <value-set>
 //	<inclusion-exclusion>     ::= <inclusion> | <exclusion>
 //	<exclusion>               ::= "notin"
@@ -812,13 +904,15 @@ func (p *Parser) parseExactValue() (sets.String, error) {
 //	<value-set>               ::= "(" <values> ")"
 //	<values>                  ::= VALUE | VALUE "," <values>
 //	<exact-match-restriction> ::= ["="|"=="|"!="] VALUE
+//	<numeric-restriction>     ::= [">"|">="|"<"|"<="] INTEGER
 //
 // KEY is a sequence of one or more characters following [ DNS_SUBDOMAIN "/" ] DNS_LABEL. Max length is 63 characters.
 // VALUE is a sequence of zero or more characters "([A-Za-z0-9_-\.])". Max length is 63 characters.
+// INTEGER is a VALUE which is a base 10, 64-bit integer.
 // Delimiter is white space: (' ', '\t')
 // Example of valid syntax:
 //
-//	"x in (foo,,baz),y,z notin ()"
+//	"x in (foo,,baz),y,z notin (),replicas>=2"
 //
 // Note:
 //
@@ -830,6 +924,11 @@ func (p *Parser) parseExactValue() (sets.String, error) {
 //	(4) A requirement with just a KEY - as in "y" above - denotes that
 //	    the KEY exists and can be any VALUE.
 //	(5) A requirement with just !KEY requires that the KEY not exist.
+//	(6) A numeric restriction denotes that the KEY exists and its VALUE is
+//	    an integer which satisfies the comparison
+//
+// The error of an invalid selector is a *ParseError, which carries the
+// position of the problem in the selector.
 func Parse(selector string) (Selector, error) {
 	parsedSelector, err := parse(selector)
 	if err == nil {
@@ -26,12 +26,15 @@ func TestSelectorParse(t *testing.T) {
 		"!x",
 		"x>1",
 		"x>1,z<5",
+		"x>=1,z<=5",
 	}
 	testBadStrings := []string{
 		"x=a||y=b",
 		"x==a==b",
 		"!x=a",
 		"x<a",
+		"x>=1.5",
+		"x<=(1,2)",
 	}
 	for _, test := range testGoodStrings {
 		lq, err := Parse(test)
@@ -176,7 +179,9 @@ func TestLexer(t *testing.T) {
 		{"=", EqualsToken},
 		{"==", DoubleEqualsToken},
 		{">", GreaterThanToken},
+		{">=", GreaterThanOrEqualsToken},
 		{"<", LessThanToken},
+		{"<=", LessThanOrEqualsToken},
 		// Note that Lex returns the longest valid token found
 		{"!", DoesNotExistToken},
 		{"!=", NotEqualsToken},
@@ -243,6 +248,9 @@ func TestLexerSequence(t *testing.T) {
 		},
 		{"key>2", []Token{IdentifierToken, GreaterThanToken, IdentifierToken}},
 		{"key<1", []Token{IdentifierToken, LessThanToken, IdentifierToken}},
+		{"key>=2", []Token{IdentifierToken, GreaterThanOrEqualsToken, IdentifierToken}},
+		{"key <= 1", []Token{IdentifierToken, LessThanOrEqualsToken, IdentifierToken}},
+		{"key>==2", []Token{IdentifierToken, GreaterThanOrEqualsToken, EqualsToken, IdentifierToken}},
 	}
 	for _, v := range testcases {
 		var literals []string
@@ -363,6 +371,11 @@ func TestRequirementConstructor(t *testing.T) {
 		{"z", selection.LessThan, sets.NewString("6"), true},
 		{"foo", selection.GreaterThan, sets.NewString("bar"), false},
 		{"barz", selection.LessThan, sets.NewString("blah"), false},
+		{"y", selection.GreaterThanOrEquals, sets.NewString("1"), true},
+		{"z", selection.LessThanOrEquals, sets.NewString("6"), true},
+		{"y", selection.GreaterThanOrEquals, sets.NewString("1.5"), false},
+		{"z", selection.LessThanOrEquals, sets.NewString("1", "2"), false},
+		{"z", selection.GreaterThan, nil, false},
 		{strings.Repeat("a", 254), selection.Exists, nil, false}, // breaks DNS rule that len(key) <= 253
 	}
 	for _, rc := range requirementConstructorTests {
@@ -412,6 +425,10 @@ func TestToString(t *testing.T) {
 			getRequirement("y", selection.LessThan, sets.NewString("8"), t),
 			getRequirement("z", selection.Exists, nil, t)},
 			"x>2,y<8,z", true},
+		{&internalSelector{
+			getRequirement("x", selection.GreaterThanOrEquals, sets.NewString("2"), t),
+			getRequirement("y", selection.LessThanOrEquals, sets.NewString("8"), t)},
+			"x>=2,y<=8", true},
 	}
 	for _, ts := range toStringTests {
 		if out := ts.In.String(); out == "" && ts.Valid {
@@ -461,6 +478,24 @@ func TestRequirementSelectorMatching(t *testing.T) {
 		{Set{"z": "v2"}, &internalSelector{
 			getRequirement("z", selection.GreaterThan, sets.NewString("1"), t),
 		}, false},
+		{Set{"z": "1"}, &internalSelector{
+			getRequirement("z", selection.GreaterThan, sets.NewString("1"), t),
+		}, false},
+		{Set{"z": "1"}, &internalSelector{
+			getRequirement("z", selection.GreaterThanOrEquals, sets.NewString("1"), t),
+		}, true},
+		{Set{"z": "0"}, &internalSelector{
+			getRequirement("z", selection.GreaterThanOrEquals, sets.NewString("1"), t),
+		}, false},
+		{Set{"z": "1"}, &internalSelector{
+			getRequirement("z", selection.LessThan, sets.NewString("1"), t),
+		}, false},
+		{Set{"z": "1"}, &internalSelector{
+			getRequirement("z", selection.LessThanOrEquals, sets.NewString("1"), t),
+		}, true},
+		{Set{"y": "1"}, &internalSelector{
+			getRequirement("z", selection.LessThanOrEquals, sets.NewString("1"), t),
+		}, false},
 	}
 	for _, lsm := range labelSelectorMatchingTests {
 		if match := lsm.Sel.Matches(lsm.Set); match != lsm.Match {
@@ -531,6 +566,16 @@ func TestSetSelectorParser(t *testing.T) {
 		{"x<7", internalSelector{
 			getRequirement("x", selection.LessThan, sets.NewString("7"), t),
 		}, true, true},
+		{"x >= 1", internalSelector{
+			getRequirement("x", selection.GreaterThanOrEquals, sets.NewString("1"), t),
+		}, true, true},
+		{"x<=7,y", internalSelector{
+			getRequirement("x", selection.LessThanOrEquals, sets.NewString("7"), t),
+			getRequirement("y", selection.Exists, nil, t),
+		}, true, true},
+		{"x>a", nil, true, false},
+		{"x>=(1,2)", nil, true, false},
+		{"x<=", nil, true, false},
 		{"x=a,y!=b", internalSelector{
 			getRequirement("x", selection.Equals, sets.NewString("a"), t),
 			getRequirement("y", selection.NotEquals, sets.NewString("b"), t),
@@ -575,6 +620,39 @@ func TestSetSelectorParser(t *testing.T) {
 	}
 }
 
+func TestParseErrorPosition(t *testing.T) {
+	testCases := []struct {
+		in       string
+		position int
+		message  string
+	}{
+		{"x>a", 2, "for 'gt' operator, the value must be an integer, found 'a'"},
+		{"x, y >= 1.5", 8, "for 'ge' operator, the value must be an integer, found '1.5'"},
+		{"x<(1,2)", 2, "for 'lt' operator, exactly one value is required, found a set of values"},
+		{"x<=,y", 3, "for 'le' operator, a value is required"},
+		{"x>=>1", 3, "found '>', expected: integer"},
+		{"x=a,-y", 4, "invalid label key"},
+		{"x in (a,b-),y", 8, "invalid label value"},
+		{"x in (a) y", 9, "found 'y', expected: ',' or 'end of string'"},
+		{"x,,y", 2, "found ',', expected: identifier after ','"},
+		{"x ~ a", 2, "found '~', expected: '=', '!=', '==', 'in', 'notin', '>', '>=', '<', '<='"},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(tc.in)
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Parse(%q) => %v, want a *ParseError", tc.in, err)
+			continue
+		}
+		if pe.Position != tc.position {
+			t.Errorf("Parse(%q) => error at position %d, want %d: %v", tc.in, pe.Position, tc.position, pe)
+		}
+		if !strings.Contains(pe.Message, tc.message) {
+			t.Errorf("Parse(%q) => %q, want message containing %q", tc.in, pe.Message, tc.message)
+		}
+	}
+}
+
 func getRequirement(key string, op selection.Operator, vals sets.String, t *testing.T) Requirement {
 	req, err := NewRequirement(key, op, vals.List())
 	if err != nil {
@@ -11,13 +11,15 @@ type Operator string
 
 // Defines some operators to use.
 const (
-	DoesNotExist Operator = "!"
-	Equals       Operator = "="
-	DoubleEquals Operator = "=="
-	In           Operator = "in"
-	NotEquals    Operator = "!="
-	NotIn        Operator = "notin"
-	Exists       Operator = "exists"
-	GreaterThan  Operator = "gt"
-	LessThan     Operator = "lt"
+	DoesNotExist        Operator = "!"
+	Equals              Operator = "="
+	DoubleEquals        Operator = "=="
+	In                  Operator = "in"
+	NotEquals           Operator = "!="
+	NotIn               Operator = "notin"
+	Exists              Operator = "exists"
+	GreaterThan         Operator = "gt"
+	GreaterThanOrEquals Operator = "ge"
+	LessThan            Operator = "lt"
+	LessThanOrEquals    Operator = "le"
 )
@@ -0,0 +1,224 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/validation/field"
+)
+
+// Store is the lookup dependency of the asynchronous rules, e.g. a database.
+type Store interface {
+	// Lookup returns the ID of the object of the kind with the given key,
+	// e.g. the name of a user, and whether it exists.
+	Lookup(ctx context.Context, kind, key string) (id string, found bool, err error)
+}
+
+// AsyncRule is a validation which needs I/O, such as a uniqueness check.
+type AsyncRule struct {
+	// Name identifies the rule. It's the origin of its errors.
+	Name string
+
+	// Path is the path of the field the rule applies to, which the errors of
+	// the rule itself, such as timeouts, are reported on.
+	Path *field.Path
+
+	// Timeout bounds the duration of the rule. Zero means no timeout besides
+	// the deadline of the context.
+	Timeout time.Duration
+
+	// Validate validates data. It must return when ctx is done.
+	Validate func(ctx context.Context, store Store, data interface{}) field.ErrorList
+}
+
+// Unique returns a rule which reports a field.Duplicate if an object of the
+// kind already has the value at path as key. The object whose ID is returned
+// by id, e.g. the validated object on updates, is ignored; id can be nil.
+// Empty values are ignored: use the required tag to require them.
+func Unique(path *field.Path, kind string, id func(data interface{}) string) AsyncRule {
+	return AsyncRule{
+		Name: "unique",
+		Path: path,
+		Validate: func(ctx context.Context, store Store, data interface{}) field.ErrorList {
+			self := ""
+			if id != nil {
+				self = id(data)
+			}
+
+			return lookupValues(ctx, store, data, path, kind, func(p *field.Path, value interface{}, found bool, objID string) *field.Error {
+				if found && (self == "" || objID != self) {
+					return field.Duplicate(p, value)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+// Exists returns a rule which reports a field.NotFound if no object of the
+// kind has the value at path as key, e.g. a referenced secret. If the value
+// is a slice or an array, every element must exist. Empty values are
+// ignored.
+func Exists(path *field.Path, kind string) AsyncRule {
+	return AsyncRule{
+		Name: "exists",
+		Path: path,
+		Validate: func(ctx context.Context, store Store, data interface{}) field.ErrorList {
+			return lookupValues(ctx, store, data, path, kind, func(p *field.Path, value interface{}, found bool, _ string) *field.Error {
+				if !found {
+					return field.NotFound(p, value)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+// lookupValues looks up the non-empty values at path, or their elements, and
+// reports the errors returned by check.
+func lookupValues(ctx context.Context, store Store, data interface{}, path *field.Path, kind string,
+	check func(p *field.Path, value interface{}, found bool, id string) *field.Error,
+) field.ErrorList {
+	value, err := path.Get(data)
+	if errors.Is(err, field.ErrNilParent) {
+		return nil
+	}
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+
+	paths, values := []*field.Path{path}, []interface{}{value}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		paths, values = paths[:0], values[:0]
+		for i := 0; i < v.Len(); i++ {
+			paths = append(paths, path.Index(i))
+			values = append(values, v.Index(i).Interface())
+		}
+	}
+
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		if v := reflect.ValueOf(value); !v.IsValid() || v.IsZero() {
+			continue
+		}
+
+		id, found, err := store.Lookup(ctx, kind, fmt.Sprint(value))
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(paths[i], err))
+			continue
+		}
+		if err := check(paths[i], value, found, id); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	return allErrs
+}
+
+// RegisterAsyncRules registers asynchronous rules for the struct type of
+// typ. They are run by ValidateContext. It must not be called concurrently
+// with the validation methods.
+func (e *Engine) RegisterAsyncRules(typ interface{}, rules ...AsyncRule) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return errors.Errorf("rules can only be registered for structs, got %T", typ)
+	}
+	for _, r := range rules {
+		if r.Validate == nil {
+			return errors.Errorf("asynchronous rule %q without Validate", r.Name)
+		}
+	}
+
+	if e.asyncRules == nil {
+		e.asyncRules = map[reflect.Type][]AsyncRule{}
+	}
+	e.asyncRules[t] = append(e.asyncRules[t], rules...)
+
+	return nil
+}
+
+// ValidateContext is like Validate, but the asynchronous rules registered for
+// the type of data are run too, concurrently, with the store as lookup
+// dependency. Their errors are appended in the order of registration; a rule
+// which doesn't complete before its timeout or the end of ctx, or panics, is
+// reported as an internal error.
+func (e *Engine) ValidateContext(ctx context.Context, store Store, data interface{}, locales ...string) field.ErrorList {
+	allErrs := e.Validate(data, locales...)
+
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	rules := e.asyncRules[t]
+
+	results := make([]field.ErrorList, len(rules))
+	var wg sync.WaitGroup
+	for i := range rules {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runAsyncRule(ctx, store, data, rules[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, errs := range results {
+		allErrs = append(allErrs, errs...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return allErrs
+}
+
+// runAsyncRule runs the rule with its timeout.
+func runAsyncRule(ctx context.Context, store Store, data interface{}, rule AsyncRule) field.ErrorList {
+	if rule.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rule.Timeout)
+		defer cancel()
+	}
+
+	var result field.ErrorList
+	done := errors.Go(func() error {
+		result = rule.Validate(ctx, store, data)
+		return nil
+	})
+
+	var errs field.ErrorList
+	select {
+	case err := <-done:
+		if err != nil {
+			// the message of the coded error doesn't tell what happened
+			var pe *errors.PanicError
+			if errors.As(err, &pe) {
+				err = errors.Errorf("rule %s panicked: %v", rule.Name, pe.Value)
+			}
+			errs = field.ErrorList{field.InternalError(rule.Path, err)}
+		} else {
+			errs = result
+		}
+	case <-ctx.Done():
+		errs = field.ErrorList{field.InternalError(rule.Path, errors.Errorf("rule %s: %v", rule.Name, ctx.Err()))}
+	}
+
+	for _, err := range errs {
+		if err.Origin == "" {
+			err.WithOrigin(rule.Name)
+		}
+	}
+
+	return errs
+}
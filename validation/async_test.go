@@ -0,0 +1,179 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+type team struct {
+	Name string `json:"name"`
+}
+
+type account struct {
+	ID         string   `json:"-"`
+	Name       string   `json:"name" validate:"required"`
+	SecretRefs []string `json:"secretRefs"`
+	Team       *team    `json:"team,omitempty"`
+}
+
+func newAsyncEngine(t *testing.T) *Engine {
+	e := NewEngine()
+	err := e.RegisterAsyncRules(account{},
+		Unique(field.NewPath("name"), "accounts", func(data interface{}) string { return data.(*account).ID }),
+		Exists(field.NewPath("secretRefs"), "secrets"),
+		Exists(field.NewPath("team", "name"), "teams"),
+	)
+	assert.NoError(t, err)
+
+	return e
+}
+
+func newFakeStore() *MemoryStore {
+	store := NewMemoryStore()
+	store.Add("accounts", "alice", "1")
+	store.Add("secrets", "db-password", "s1")
+	store.Add("teams", "platform", "t1")
+
+	return store
+}
+
+func TestValidateContext(t *testing.T) {
+	e := newAsyncEngine(t)
+	store := newFakeStore()
+
+	tests := []struct {
+		data *account
+		want field.ErrorList
+	}{
+		{&account{Name: "bob", SecretRefs: []string{"db-password"}, Team: &team{Name: "platform"}}, nil},
+		{&account{ID: "1", Name: "alice"}, nil},
+		{
+			&account{ID: "2", Name: "alice"},
+			field.ErrorList{field.Duplicate(field.NewPath("name"), "alice").WithOrigin("unique")},
+		},
+		{
+			&account{Name: "bob", SecretRefs: []string{"db-password", "api-key", ""}, Team: &team{Name: "sre"}},
+			field.ErrorList{
+				field.NotFound(field.NewPath("secretRefs").Index(1), "api-key").WithOrigin("exists"),
+				field.NotFound(field.NewPath("team", "name"), "sre").WithOrigin("exists"),
+			},
+		},
+		{
+			&account{Name: "", SecretRefs: []string{"api-key"}},
+			field.ErrorList{
+				field.Invalid(field.NewPath("name"), "", "name is a required field").WithOrigin("required"),
+				field.NotFound(field.NewPath("secretRefs").Index(0), "api-key").WithOrigin("exists"),
+			},
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.want, e.ValidateContext(context.Background(), store, test.data), "test %d", i+1)
+	}
+
+	assert.Error(t, e.RegisterAsyncRules("account", Exists(nil, "kind")))
+	assert.Error(t, e.RegisterAsyncRules(account{}, AsyncRule{Name: "empty"}))
+}
+
+func TestValidateContextTimeout(t *testing.T) {
+	e := NewEngine()
+	rule := Exists(field.NewPath("team", "name"), "teams")
+	rule.Timeout = 10 * time.Millisecond
+	assert.NoError(t, e.RegisterAsyncRules(account{}, rule, AsyncRule{
+		Name: "panics",
+		Path: field.NewPath("name"),
+		Validate: func(ctx context.Context, store Store, data interface{}) field.ErrorList {
+			panic("boom")
+		},
+	}))
+
+	store := newFakeStore()
+	store.Delay = time.Second
+
+	errs := e.ValidateContext(context.Background(), store, &account{Name: "bob", Team: &team{Name: "platform"}})
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, field.ErrorTypeInternal, errs[0].Type)
+		assert.Equal(t, "team.name", errs[0].Field)
+		assert.Equal(t, "exists", errs[0].Origin)
+		assert.Contains(t, errs[0].Detail, "deadline exceeded")
+
+		assert.Equal(t, field.ErrorTypeInternal, errs[1].Type)
+		assert.Equal(t, "name", errs[1].Field)
+		assert.Equal(t, "panics", errs[1].Origin)
+		assert.Equal(t, "rule panics panicked: boom", errs[1].Detail)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = e.ValidateContext(ctx, store, &account{Name: "bob", Team: &team{Name: "platform"}})
+	if assert.Len(t, errs, 2) {
+		assert.Contains(t, errs[0].Detail, "context canceled")
+	}
+}
+
+func TestValidateContextConcurrent(t *testing.T) {
+	// every rule waits for the others to start: they would time out if they
+	// were run one after the other
+	const n = 4
+	var started sync.WaitGroup
+	started.Add(n)
+	barrier := AsyncRule{
+		Name:    "barrier",
+		Timeout: time.Second,
+		Validate: func(ctx context.Context, store Store, data interface{}) field.ErrorList {
+			started.Done()
+			done := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return field.ErrorList{field.InternalError(nil, ctx.Err())}
+			}
+		},
+	}
+
+	e := NewEngine()
+	for i := 0; i < n; i++ {
+		assert.NoError(t, e.RegisterAsyncRules(&account{}, barrier))
+	}
+
+	assert.Nil(t, e.ValidateContext(context.Background(), NewMemoryStore(), &account{Name: "bob"}))
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	store.Add("secrets", "db-password", "s1")
+
+	id, found, err := store.Lookup(context.Background(), "secrets", "db-password")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "s1", id)
+
+	store.Remove("secrets", "db-password")
+	_, found, err = store.Lookup(context.Background(), "secrets", "db-password")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, _ = store.Lookup(context.Background(), "unknown", "key")
+	assert.False(t, found)
+
+	zero := &MemoryStore{}
+	zero.Add("secrets", "db-password", "s1")
+	_, found, _ = zero.Lookup(context.Background(), "secrets", "db-password")
+	assert.True(t, found)
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+// indexPattern matches the index of slice, array and map elements in a path.
+var indexPattern = regexp.MustCompile(`\[[^\]]*\]`)
+
+// Engine validates values against their `validate` struct tags and their
+// declarative rules (see RulesTag) and returns the failures as a
+// field.ErrorList. The paths of the errors are made of the
+// `json` tag names of the fields, relative to the validated value, e.g.
+// spec.containers[0].name.
+//
+// An Engine is meant to be created once, configured with the Register
+// methods, and then used to validate many values. The Register methods must
+// not be called concurrently with the validation methods; the validation
+// methods are safe for concurrent use.
+type Engine struct {
+	val         *validator.Validate
+	transMux    sync.RWMutex
+	translators map[string]ut.Translator // created on first use, see translatorOf
+	rules       map[reflect.Type][]*compiledRule
+	asyncRules  map[reflect.Type][]AsyncRule
+	aliases     map[string]string
+	schemas     map[string]SchemaFunc
+	jsonNames   bool
+}
+
+// NewEngine creates an Engine with the built-in tags of the validator, the
+// dir, file, description and name tags, and the translations of every
+// registered locale. The translations of a locale are registered the first
+// time it's used.
+func NewEngine() *Engine {
+	return newEngine(true)
+}
+
+// newEngine creates an Engine. If jsonNames is false, the paths of the errors
+// are the namespaces of the validator, made of the Go names of the type and
+// of the fields.
+func newEngine(jsonNames bool) *Engine {
+	result := validator.New()
+
+	// independent validators
+	result.RegisterValidation("dir", validateDir)                 // nolint: errcheck // no need
+	result.RegisterValidation("file", validateFile)               // nolint: errcheck // no need
+	result.RegisterValidation("description", validateDescription) // nolint: errcheck // no need
+	result.RegisterValidation("name", validateName)               // nolint: errcheck // no need
+
+	if jsonNames {
+		result.RegisterTagNameFunc(jsonTagName)
+	}
+
+	return &Engine{
+		val:         result,
+		translators: map[string]ut.Translator{},
+		jsonNames:   jsonNames,
+	}
+}
+
+// RegisterValidation adds a validation with the given tag. Register its
+// messages with RegisterTranslation.
+func (e *Engine) RegisterValidation(tag string, fn validator.Func, callValidationEvenIfNull ...bool) error {
+	return e.val.RegisterValidation(tag, fn, callValidationEvenIfNull...)
+}
+
+// RegisterStructValidation registers a struct-level validation for the types
+// of the given values. The errors reported by fn are translated by their tag.
+func (e *Engine) RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	e.val.RegisterStructValidation(fn, types...)
+}
+
+// RegisterAlias registers an alias for a list of tags, e.g. "iscolor" for
+// "hexcolor|rgb|rgba". Errors are reported with the alias as tag.
+func (e *Engine) RegisterAlias(alias, tags string) {
+	e.val.RegisterAlias(alias, tags)
+
+	if e.aliases == nil {
+		e.aliases = map[string]string{}
+	}
+	e.aliases[alias] = tags
+}
+
+// Validate validates data, a struct or a pointer to a struct. The details of
+// the errors are translated to the first supported of the given locales, or
+// to the DefaultLocale.
+func (e *Engine) Validate(data interface{}, locales ...string) field.ErrorList {
+	allErrs := e.errorList(data, e.val.Struct(data), e.translator(locales...))
+	if e.jsonNames {
+		allErrs = append(allErrs, e.evaluateRules(reflect.ValueOf(data), nil)...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return allErrs
+}
+
+// ValidatePartial is like Validate, but only the fields at the given paths,
+// their ancestors and their descendants are validated, e.g. the fields of a
+// PATCH request. Paths are made of json names, like the paths of the errors;
+// indexes are ignored. Unknown paths are reported as not found.
+func (e *Engine) ValidatePartial(data interface{}, paths []string, locales ...string) field.ErrorList {
+	typ := reflect.TypeOf(data)
+
+	allErrs := field.ErrorList{}
+	selected := make([]string, 0, len(paths))
+	for _, path := range paths {
+		goPath, ok := goFieldPath(typ, path)
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(field.NewPath(path), path))
+			continue
+		}
+		selected = append(selected, goPath)
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	prefix := typeName(data)
+	err := e.val.StructFiltered(data, func(ns []byte) bool {
+		return !isSelected(strings.TrimPrefix(string(ns), prefix), selected)
+	})
+	allErrs = e.errorList(data, err, e.translator(locales...))
+
+	for _, ruleErr := range e.evaluateRules(reflect.ValueOf(data), nil) {
+		if isSelected(ruleErr.Field, paths) {
+			allErrs = append(allErrs, ruleErr)
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return allErrs
+}
+
+// isSelected reports whether path, its ancestors or its descendants are
+// selected. Indexes are ignored.
+func isSelected(path string, selected []string) bool {
+	path = indexPattern.ReplaceAllString(path, "")
+	for _, s := range selected {
+		s = indexPattern.ReplaceAllString(s, "")
+		if path == s || strings.HasPrefix(s, path+".") || strings.HasPrefix(path, s+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// errorList converts the errors returned by the validator.
+func (e *Engine) errorList(data interface{}, err error, trans ut.Translator) field.ErrorList {
+	if err == nil {
+		return nil
+	}
+
+	// this check is only needed when your code could produce
+	// an invalid value for validation such as interface with nil
+	// value most including myself do not usually have code like this.
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		return field.ErrorList{field.Invalid(field.NewPath(""), err.Error(), "")}
+	}
+
+	prefix := ""
+	if e.jsonNames {
+		prefix = typeName(data)
+	}
+
+	allErrs := field.ErrorList{}
+
+	// the translations can be registered concurrently, see translatorOf
+	e.transMux.RLock()
+	defer e.transMux.RUnlock()
+
+	// collect human-readable errors
+	vErrors, _ := err.(validator.ValidationErrors)
+	for _, vErr := range vErrors {
+		path := strings.TrimPrefix(vErr.Namespace(), prefix)
+		allErrs = append(allErrs, field.Invalid(field.NewPath(path), vErr.Value(), vErr.Translate(trans)).WithOrigin(vErr.Tag()))
+	}
+
+	return allErrs
+}
+
+// typeName returns the prefix of the namespaces of the validator for the
+// fields of data: the name of its type followed by a dot, if it's named.
+func typeName(data interface{}) string {
+	typ := reflect.TypeOf(data)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Name() == "" {
+		return ""
+	}
+
+	return typ.Name() + "."
+}
+
+// jsonTagName returns the name of the field in its json tag, or an empty
+// string to use the Go name.
+func jsonTagName(fld reflect.StructField) string {
+	name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// goFieldPath converts a path of json names into the path of the Go names of
+// the fields, without indexes.
+func goFieldPath(typ reflect.Type, path string) (string, bool) {
+	names := []string{}
+	for _, segment := range strings.Split(indexPattern.ReplaceAllString(path, ""), ".") {
+		for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice ||
+			typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			return "", false
+		}
+
+		fld, ok := field.JSONField(typ, segment)
+		if !ok {
+			return "", false
+		}
+		// the embedded structs are in the namespaces of the validator
+		for _, index := range fld.Index {
+			if typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+			}
+			names = append(names, typ.Field(index).Name)
+			typ = typ.Field(index).Type
+		}
+	}
+
+	return strings.Join(names, "."), true
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+type container struct {
+	Name  string `json:"name" validate:"required"`
+	Image string `json:"image" validate:"required,image"`
+}
+
+type podSpec struct {
+	Containers []container `json:"containers" validate:"required,dive"`
+	Replicas   int         `json:"replicas" validate:"min=1"`
+	MinReady   int         `json:"minReady"`
+}
+
+type pod struct {
+	Name   string            `json:"name" validate:"required,name"`
+	Labels map[string]string `json:"labels,omitempty" validate:"dive,keys,required,endkeys,required"`
+	Spec   podSpec           `json:"spec"`
+	Secret string            `json:"-" validate:"omitempty,min=8"`
+}
+
+func newTestEngine() *Engine {
+	e := NewEngine()
+	e.RegisterAlias("image", "required,contains=:")
+	e.RegisterStructValidation(func(sl validator.StructLevel) {
+		spec := sl.Current().Interface().(podSpec)
+		if spec.MinReady > spec.Replicas {
+			sl.ReportError(spec.MinReady, "minReady", "MinReady", "ltefield", "replicas")
+		}
+	}, podSpec{})
+	_ = e.RegisterTranslation("en", "image", "{0} must be an image reference like name:tag")
+	_ = e.RegisterTranslation("zh", "image", "{0}必须是形如name:tag的镜像")
+
+	return e
+}
+
+func validPod() *pod {
+	return &pod{
+		Name:   "nginx",
+		Labels: map[string]string{"app": "nginx"},
+		Spec: podSpec{
+			Containers: []container{{Name: "nginx", Image: "nginx:1.25"}},
+			Replicas:   2,
+			MinReady:   1,
+		},
+		Secret: "secretvalue",
+	}
+}
+
+func fieldsOf(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+
+	return fields
+}
+
+func TestEngineValidate(t *testing.T) {
+	e := newTestEngine()
+
+	tests := []struct {
+		modify func(p *pod)
+		want   []string
+	}{
+		{func(p *pod) {}, []string{}},
+		{func(p *pod) { p.Name = "" }, []string{"name"}},
+		{func(p *pod) { p.Labels[""] = "empty" }, []string{"labels[]"}},
+		{func(p *pod) { p.Spec.Containers[0].Image = "nginx" }, []string{"spec.containers[0].image"}},
+		{func(p *pod) { p.Spec.Containers = nil }, []string{"spec.containers"}},
+		{func(p *pod) { p.Spec.MinReady = 3 }, []string{"spec.minReady"}},
+		{func(p *pod) { p.Secret = "short" }, []string{"Secret"}},
+		{func(p *pod) { p.Name = ""; p.Spec.Replicas = 0; p.Spec.MinReady = 0 }, []string{"name", "spec.replicas"}},
+	}
+
+	for i, test := range tests {
+		p := validPod()
+		test.modify(p)
+		errs := e.Validate(p)
+		assert.Equal(t, test.want, fieldsOf(errs), "test %d: %v", i+1, errs)
+	}
+
+	p := validPod()
+	p.Spec.Containers[0].Image = "nginx"
+	errs := e.Validate(p, "zh-CN")
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "image必须是形如name:tag的镜像", errs[0].Detail)
+		assert.Equal(t, "nginx", errs[0].BadValue)
+	}
+	errs = e.Validate(p)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "image must be an image reference like name:tag", errs[0].Detail)
+	}
+
+	assert.Len(t, e.Validate(nil), 1)
+}
+
+func TestEngineValidatePartial(t *testing.T) {
+	e := newTestEngine()
+
+	p := validPod()
+	p.Name = ""
+	p.Spec.Replicas = 0
+	p.Spec.MinReady = 0
+	p.Spec.Containers[0].Image = "nginx"
+
+	tests := []struct {
+		paths []string
+		want  []string
+	}{
+		{nil, []string{}},
+		{[]string{"name"}, []string{"name"}},
+		{[]string{"spec.replicas"}, []string{"spec.replicas"}},
+		{[]string{"spec"}, []string{"spec.replicas", "spec.containers[0].image"}},
+		{[]string{"spec.containers[0].image"}, []string{"spec.containers[0].image"}},
+		{[]string{"labels", "spec.containers.name"}, []string{}},
+		{[]string{"spec.unknown"}, []string{"spec.unknown"}},
+	}
+
+	for i, test := range tests {
+		errs := e.ValidatePartial(p, test.paths)
+		assert.ElementsMatch(t, test.want, fieldsOf(errs), "test %d: %v", i+1, errs)
+	}
+
+	errs := e.ValidatePartial(p, []string{"spec.unknown"})
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, field.ErrorTypeNotFound, errs[0].Type)
+	}
+}
+
+func TestEngineConcurrent(t *testing.T) {
+	e := newTestEngine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			p := validPod()
+			p.Spec.Replicas = 0
+			p.Spec.MinReady = 0
+			locale := LocaleEnglish
+			if i%2 == 0 {
+				locale = LocaleChinese
+			}
+			errs := e.Validate(p, locale)
+			assert.Equal(t, []string{"spec.replicas"}, fieldsOf(errs))
+		}(i)
+	}
+	wg.Wait()
+}
@@ -21,10 +21,21 @@ type Error struct {
 	Field    string
 	BadValue interface{}
 	Detail   string
+
+	// Origin is the validation which reported the error, e.g. the tag of the
+	// validator, for clients which need more than the type. It's optional.
+	Origin string
 }
 
 var _ error = &Error{}
 
+// WithOrigin sets the origin of the error and returns it.
+func (v *Error) WithOrigin(origin string) *Error {
+	v.Origin = origin
+
+	return v
+}
+
 // Error implements the error interface.
 func (v *Error) Error() string {
 	return fmt.Sprintf("%s: %s", v.Field, v.ErrorBody())
@@ -143,26 +154,26 @@ func (t ErrorType) String() string {
 // NotFound returns a *Error indicating "value not found".  This is
 // used to report failure to find a requested value (e.g. looking up an ID).
 func NotFound(field *Path, value interface{}) *Error {
-	return &Error{ErrorTypeNotFound, field.String(), value, ""}
+	return &Error{Type: ErrorTypeNotFound, Field: field.String(), BadValue: value, Detail: ""}
 }
 
 // Required returns a *Error indicating "value required".  This is used
 // to report required values that are not provided (e.g. empty strings, null
 // values, or empty arrays).
 func Required(field *Path, detail string) *Error {
-	return &Error{ErrorTypeRequired, field.String(), "", detail}
+	return &Error{Type: ErrorTypeRequired, Field: field.String(), BadValue: "", Detail: detail}
 }
 
 // Duplicate returns a *Error indicating "duplicate value".  This is
 // used to report collisions of values that must be unique (e.g. names or IDs).
 func Duplicate(field *Path, value interface{}) *Error {
-	return &Error{ErrorTypeDuplicate, field.String(), value, ""}
+	return &Error{Type: ErrorTypeDuplicate, Field: field.String(), BadValue: value, Detail: ""}
 }
 
 // Invalid returns a *Error indicating "invalid value".  This is used
 // to report malformed values (e.g. failed regex match, too long, out of bounds).
 func Invalid(field *Path, value interface{}, detail string) *Error {
-	return &Error{ErrorTypeInvalid, field.String(), value, detail}
+	return &Error{Type: ErrorTypeInvalid, Field: field.String(), BadValue: value, Detail: detail}
 }
 
 // NotSupported returns a *Error indicating "unsupported value".
@@ -177,7 +188,7 @@ func NotSupported(field *Path, value interface{}, validValues []string) *Error {
 		}
 		detail = "supported values: " + strings.Join(quotedValues, ", ")
 	}
-	return &Error{ErrorTypeNotSupported, field.String(), value, detail}
+	return &Error{Type: ErrorTypeNotSupported, Field: field.String(), BadValue: value, Detail: detail}
 }
 
 // Forbidden returns a *Error indicating "forbidden".  This is used to
@@ -185,7 +196,7 @@ func NotSupported(field *Path, value interface{}, validValues []string) *Error {
 // some conditions, but which are not permitted by current conditions (e.g.
 // security policy).
 func Forbidden(field *Path, detail string) *Error {
-	return &Error{ErrorTypeForbidden, field.String(), "", detail}
+	return &Error{Type: ErrorTypeForbidden, Field: field.String(), BadValue: "", Detail: detail}
 }
 
 // TooLong returns a *Error indicating "too long".  This is used to
@@ -193,7 +204,12 @@ func Forbidden(field *Path, detail string) *Error {
 // Invalid, but the returned error will not include the too-long
 // value.
 func TooLong(field *Path, value interface{}, maxLength int) *Error {
-	return &Error{ErrorTypeTooLong, field.String(), value, fmt.Sprintf("must have at most %d bytes", maxLength)}
+	return &Error{
+		Type:     ErrorTypeTooLong,
+		Field:    field.String(),
+		BadValue: value,
+		Detail:   fmt.Sprintf("must have at most %d bytes", maxLength),
+	}
 }
 
 // TooMany returns a *Error indicating "too many". This is used to
@@ -201,10 +217,10 @@ func TooLong(field *Path, value interface{}, maxLength int) *Error {
 // but the returned error indicates quantity instead of length.
 func TooMany(field *Path, actualQuantity, maxQuantity int) *Error {
 	return &Error{
-		ErrorTypeTooMany,
-		field.String(),
-		actualQuantity,
-		fmt.Sprintf("must have at most %d items", maxQuantity),
+		Type:     ErrorTypeTooMany,
+		Field:    field.String(),
+		BadValue: actualQuantity,
+		Detail:   fmt.Sprintf("must have at most %d items", maxQuantity),
 	}
 }
 
@@ -212,7 +228,7 @@ func TooMany(field *Path, actualQuantity, maxQuantity int) *Error {
 // to signal that an error was found that was not directly related to user
 // input.  The err argument must be non-nil.
 func InternalError(field *Path, err error) *Error {
-	return &Error{ErrorTypeInternal, field.String(), nil, err.Error()}
+	return &Error{Type: ErrorTypeInternal, Field: field.String(), BadValue: nil, Detail: err.Error()}
 }
 
 // ErrorList holds a set of Errors.  It is plausible that we might one day have
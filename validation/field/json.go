@@ -0,0 +1,117 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/coding-hui/common/errors"
+)
+
+// errorJSON is the JSON encoding of an Error.
+type errorJSON struct {
+	Type     ErrorType   `json:"type"`
+	Field    string      `json:"field"`
+	BadValue interface{} `json:"badValue,omitempty"`
+	Detail   string      `json:"detail,omitempty"`
+	Origin   string      `json:"origin,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Like ErrorBody, the bad value is
+// omitted for the types of errors which don't report it, and values which
+// can't be encoded are encoded as their %v representation.
+func (v *Error) MarshalJSON() ([]byte, error) {
+	e := errorJSON{
+		Type:   v.Type,
+		Field:  v.Field,
+		Detail: v.Detail,
+		Origin: v.Origin,
+	}
+
+	switch v.Type {
+	//nolint: exhaustive
+	case ErrorTypeRequired, ErrorTypeForbidden, ErrorTypeTooLong, ErrorTypeInternal:
+	default:
+		e.BadValue = v.BadValue
+		if _, err := json.Marshal(v.BadValue); err != nil {
+			e.BadValue = fmt.Sprintf("%v", v.BadValue)
+		}
+	}
+
+	return json.Marshal(e)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The bad value is decoded as a
+// generic JSON value, e.g. numbers are decoded as float64.
+func (v *Error) UnmarshalJSON(data []byte) error {
+	var e errorJSON
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+	if e.Type == "" {
+		return errors.Errorf("field error without type: %s", data)
+	}
+
+	*v = Error{
+		Type:     e.Type,
+		Field:    e.Field,
+		BadValue: e.BadValue,
+		Detail:   e.Detail,
+		Origin:   e.Origin,
+	}
+	if v.BadValue == nil && (v.Type == ErrorTypeRequired || v.Type == ErrorTypeForbidden) {
+		v.BadValue = ""
+	}
+
+	return nil
+}
+
+// WithCode returns a coded error carrying the list, or nil if the list is
+// empty. The list can be retrieved with FromError.
+func (list ErrorList) WithCode(code int) error {
+	agg := list.ToAggregate()
+	if agg == nil {
+		return nil
+	}
+
+	return errors.WrapC(agg, code, "invalid fields")
+}
+
+// FromError returns the field errors carried by the error chain of err, e.g.
+// by an error returned by ErrorList.WithCode, or nil.
+func FromError(err error) ErrorList {
+	var agg errors.Aggregate
+	if !errors.As(err, &agg) {
+		return nil
+	}
+
+	var list ErrorList
+	for _, e := range agg.Errors() {
+		if fe, ok := e.(*Error); ok {
+			list = append(list, fe)
+		}
+	}
+
+	return list
+}
+
+// PublicError is the public representation of an error carrying field
+// errors, e.g. the body of an HTTP 422 response. Clients decode it to map the
+// errors back to the fields of their forms.
+type PublicError struct {
+	errors.PublicError
+
+	// Errors are the field errors, which can be empty.
+	Errors ErrorList `json:"errors,omitempty"`
+}
+
+// Public returns the public representation of err and of its field errors.
+func Public(err error) PublicError {
+	return PublicError{
+		PublicError: errors.Public(err),
+		Errors:      FromError(err),
+	}
+}
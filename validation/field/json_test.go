@@ -0,0 +1,130 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/coding-hui/common/errors"
+)
+
+func TestErrorJSON(t *testing.T) {
+	testCases := []struct {
+		err      *Error
+		expected string
+	}{
+		{
+			Invalid(NewPath("spec", "replicas"), 0, "must be at least 1").WithOrigin("min"),
+			`{"type":"FieldValueInvalid","field":"spec.replicas","badValue":0,"detail":"must be at least 1","origin":"min"}`,
+		},
+		{
+			Required(NewPath("name"), ""),
+			`{"type":"FieldValueRequired","field":"name"}`,
+		},
+		{
+			TooLong(NewPath("description"), "secret text", 8),
+			`{"type":"FieldValueTooLong","field":"description","detail":"must have at most 8 bytes"}`,
+		},
+		{
+			NotSupported(NewPath("kind"), "cron", []string{"web"}),
+			`{"type":"FieldValueNotSupported","field":"kind","badValue":"cron","detail":"supported values: \"web\""}`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		data, err := json.Marshal(testCase.err)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i+1, err)
+			continue
+		}
+		if string(data) != testCase.expected {
+			t.Errorf("test %d: expected %s, got %s", i+1, testCase.expected, data)
+		}
+	}
+
+	data, err := json.Marshal(Invalid(NewPath("events"), make(chan int), ""))
+	if err != nil {
+		t.Fatalf("unexpected error for a value which can't be encoded: %v", err)
+	}
+	var decoded Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := decoded.BadValue.(string); !ok {
+		t.Errorf("expected the value to be encoded as a string, got %#v", decoded.BadValue)
+	}
+}
+
+func TestErrorListJSONRoundTrip(t *testing.T) {
+	list := ErrorList{
+		Invalid(NewPath("name"), "-nginx", "must start with an alphanumeric character").WithOrigin("name"),
+		Required(NewPath("spec", "containers"), "at least one container"),
+		NotFound(NewPath("spec", "volumes").Index(0), "data"),
+		Forbidden(NewPath("spec", "hostNetwork"), "disallowed by policy"),
+		Duplicate(NewPath("labels").Key("app"), true),
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded ErrorList
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(list, decoded) {
+		t.Errorf("expected %v, got %v", list, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`[{"field":"name"}]`), &decoded); err == nil {
+		t.Errorf("expected an error for an error without type")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	catalog := &errors.Catalog{Codes: []errors.CoderSpec{{Code: 990001, HTTPStatus: 422, Message: "Invalid fields"}}}
+	for _, coder := range catalog.Coders() {
+		errors.Register(coder)
+	}
+
+	list := ErrorList{
+		Invalid(NewPath("name"), "-nginx", "invalid name"),
+		Required(NewPath("image"), ""),
+	}
+
+	if err := (ErrorList{}).WithCode(990001); err != nil {
+		t.Errorf("expected nil for an empty list, got %v", err)
+	}
+
+	err := errors.Wrap(list.WithCode(990001), "create app")
+	if !errors.IsCode(err, 990001) {
+		t.Errorf("expected the code to be 990001: %v", err)
+	}
+	if got := FromError(err); !reflect.DeepEqual(list, got) {
+		t.Errorf("expected %v, got %v", list, got)
+	}
+	if got := FromError(fmt.Errorf("plain")); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+
+	data, err := json.Marshal(Public(err))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var public PublicError
+	if err := json.Unmarshal(data, &public); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if public.Code != 990001 || public.Message != "Invalid fields" {
+		t.Errorf("expected code 990001 and its message, got %d, %q", public.Code, public.Message)
+	}
+	if !reflect.DeepEqual(list, public.Errors) {
+		t.Errorf("expected %v, got %v", list, public.Errors)
+	}
+}
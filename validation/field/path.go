@@ -9,6 +9,9 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
+
+	"github.com/coding-hui/common/errors"
 )
 
 // Path represents the path from some root to a particular field.
@@ -78,3 +81,57 @@ func (p *Path) String() string {
 	}
 	return buf.String()
 }
+
+// ParsePath parses the string representation of a Path, e.g.
+// spec.containers[0].env[FOO]. Subscripts are parsed as keys, which is the
+// same as indexes.
+func ParsePath(s string) (*Path, error) {
+	if s == "" {
+		return nil, errors.New("empty path")
+	}
+
+	var p *Path
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, pathError(s, i, "unterminated subscript")
+			}
+			p = p.Key(s[i+1 : i+end])
+			i += end + 1
+			continue
+		case ']':
+			return nil, pathError(s, i, "unexpected ']'")
+		case '.':
+			if p == nil {
+				return nil, pathError(s, i, "unexpected '.'")
+			}
+			i++
+		default:
+			if p != nil {
+				return nil, pathError(s, i, "expected '.' or '['")
+			}
+		}
+
+		end := strings.IndexAny(s[i:], ".[]")
+		if end < 0 {
+			end = len(s) - i
+		}
+		if end == 0 {
+			return nil, pathError(s, i, "empty field name")
+		}
+		if p == nil {
+			p = NewPath(s[i : i+end])
+		} else {
+			p = p.Child(s[i : i+end])
+		}
+		i += end
+	}
+
+	return p, nil
+}
+
+func pathError(s string, offset int, msg string) error {
+	return errors.Errorf("invalid path %q at offset %d: %s", s, offset, msg)
+}
@@ -4,7 +4,11 @@
 
 package field
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestPath(t *testing.T) {
 	testCases := []struct {
@@ -109,3 +113,45 @@ func TestPathMultiArg(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePath(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected *Path
+		err      string
+	}{
+		{"spec", NewPath("spec"), ""},
+		{"spec.containers[0].env[FOO]", NewPath("spec", "containers").Index(0).Child("env").Key("FOO"), ""},
+		{"metadata.labels[app.kubernetes.io/name]", NewPath("metadata", "labels").Key("app.kubernetes.io/name"), ""},
+		{"items[0][1]", NewPath("items").Index(0).Index(1), ""},
+		{"[3].name", (*Path)(nil).Index(3).Child("name"), ""},
+		{"labels[]", NewPath("labels").Key(""), ""},
+		{"", nil, "empty path"},
+		{"spec..name", nil, "at offset 5: empty field name"},
+		{"spec.", nil, "at offset 5: empty field name"},
+		{".spec", nil, "at offset 0: unexpected '.'"},
+		{"spec[0", nil, "at offset 4: unterminated subscript"},
+		{"spec]", nil, "at offset 4: unexpected ']'"},
+		{"spec[0]name", nil, "at offset 7: expected '.' or '['"},
+	}
+
+	for i, tc := range testCases {
+		p, err := ParsePath(tc.input)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("[%d] Expected error %q, got %v", i, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(p, tc.expected) {
+			t.Errorf("[%d] Expected %#v, got %#v", i, tc.expected, p)
+		}
+		if p.String() != tc.input {
+			t.Errorf("[%d] Expected %q, got %q", i, tc.input, p.String())
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package field
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/coding-hui/common/errors"
+)
+
+// ErrNilParent is the cause of the errors of Get and Set when a parent of the
+// value at the path is nil, e.g. an optional struct which is not set.
+var ErrNilParent = errors.New("parent is nil")
+
+// Get returns the value at the path in obj, a struct, map, slice or array,
+// or a pointer to one. Fields are selected by their json name, or by their Go
+// name if they have none, and the fields of embedded structs are promoted
+// like in encoding/json. Map keys are selected by subscripts or by field
+// names.
+func (p *Path) Get(obj interface{}) (interface{}, error) {
+	v := reflect.ValueOf(obj)
+	for _, elem := range p.elements() {
+		var err error
+		if v, err = elem.lookup(v); err != nil {
+			return nil, err
+		}
+	}
+
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, nil
+	}
+
+	return v.Interface(), nil
+}
+
+// Set sets the value at the path in obj, which must be a non-nil pointer.
+// The nil pointers and maps along the path are allocated, but slices are not
+// extended. The value must be assignable or convertible to the type of the
+// target, or to the type it points to, e.g. a float64 decoded from JSON can be
+// set to an int field; nil sets the zero value.
+func (p *Path) Set(obj interface{}, value interface{}) error {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("cannot set %s in %T: not a non-nil pointer", p, obj)
+	}
+
+	return set(v.Elem(), p.elements(), value)
+}
+
+// elements returns the elements of the path from the root.
+func (p *Path) elements() []*Path {
+	elems := []*Path{}
+	for ; p != nil; p = p.parent {
+		elems = append(elems, p)
+	}
+	for i, j := 0, len(elems)-1; i < j; i, j = i+1, j-1 {
+		elems[i], elems[j] = elems[j], elems[i]
+	}
+
+	return elems
+}
+
+// selector returns the field name or the subscript of the element.
+func (p *Path) selector() string {
+	if p.name != "" {
+		return p.name
+	}
+
+	return p.index
+}
+
+// lookup returns the value selected by the element in v.
+func (p *Path) lookup(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, errors.WithMessagef(ErrNilParent, "%s: parent is nil", p)
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && p.name != "":
+		if fld, ok := fieldByJSONName(v, p.name, false); ok {
+			return fld, nil
+		}
+	case v.Kind() == reflect.Map:
+		key, err := mapKey(v.Type().Key(), p.selector())
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("%s: %v", p, err)
+		}
+		if elem := v.MapIndex(key); elem.IsValid() {
+			return elem, nil
+		}
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && p.name == "":
+		i, err := sliceIndex(v, p.index)
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("%s: %v", p, err)
+		}
+		return v.Index(i), nil
+	case !v.IsValid():
+		return reflect.Value{}, errors.WithMessagef(ErrNilParent, "%s: parent is nil", p)
+	default:
+		return reflect.Value{}, errors.Errorf("%s: cannot select %q in %s", p, p.selector(), v.Type())
+	}
+
+	return reflect.Value{}, errors.Errorf("%s: not found", p)
+}
+
+// set sets the value at the elements in the settable value v.
+func set(v reflect.Value, elems []*Path, value interface{}) error {
+	if len(elems) == 0 {
+		return assign(v, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return set(v.Elem(), elems, value)
+	case reflect.Interface:
+		if v.IsNil() {
+			return errors.WithMessagef(ErrNilParent, "%s: parent is nil", elems[0])
+		}
+		// the value of an interface is not settable: set a copy
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := set(elem, elems, value); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	p := elems[0]
+	switch {
+	case v.Kind() == reflect.Struct && p.name != "":
+		fld, ok := fieldByJSONName(v, p.name, true)
+		if !ok {
+			return errors.Errorf("%s: not found", p)
+		}
+		return set(fld, elems[1:], value)
+	case v.Kind() == reflect.Map:
+		key, err := mapKey(v.Type().Key(), p.selector())
+		if err != nil {
+			return errors.Errorf("%s: %v", p, err)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		// map elements are not settable: set a copy
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if current := v.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := set(elem, elems[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && p.name == "":
+		i, err := sliceIndex(v, p.index)
+		if err != nil {
+			return errors.Errorf("%s: %v", p, err)
+		}
+		return set(v.Index(i), elems[1:], value)
+	}
+
+	return errors.Errorf("%s: cannot select %q in %s", p, p.selector(), v.Type())
+}
+
+// assign assigns value to the settable value v.
+func assign(v reflect.Value, value interface{}) error {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(v.Type()):
+		v.Set(rv)
+	case v.Kind() == reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := assign(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case (isNumber(rv.Kind()) && isNumber(v.Kind()) || rv.Kind() == v.Kind()) && rv.Type().ConvertibleTo(v.Type()):
+		v.Set(rv.Convert(v.Type()))
+	default:
+		return errors.Errorf("cannot assign %T to %s", value, v.Type())
+	}
+
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// JSONField returns the field of the struct type t with the given json name,
+// or Go name if it has no json name, including the fields of embedded
+// structs. The Index of the field is the sequence of indexes for
+// reflect.Value.FieldByIndex.
+func JSONField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		tag := fld.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		fldName := strings.SplitN(tag, ",", 2)[0]
+
+		if fld.Anonymous && fldName == "" {
+			embedded := fld.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if result, ok := JSONField(embedded, name); ok {
+					result.Index = append([]int{i}, result.Index...)
+					return result, true
+				}
+				continue
+			}
+		}
+
+		if fld.PkgPath != "" {
+			continue
+		}
+		if fldName == "" {
+			fldName = fld.Name
+		}
+		if fldName == name {
+			return fld, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// fieldByJSONName returns the field of the struct v with the given json
+// name, see JSONField. If alloc is true, nil embedded pointers to exported
+// types are allocated.
+func fieldByJSONName(v reflect.Value, name string, alloc bool) (reflect.Value, bool) {
+	fld, ok := JSONField(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	for i, index := range fld.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(index)
+	}
+
+	return v, true
+}
+
+// mapKey converts a subscript to a key of type t.
+func mapKey(t reflect.Type, s string) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("invalid key %q for %s", s, t)
+		}
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, errors.Errorf("invalid key %q for %s", s, t)
+		}
+		key.SetUint(n)
+	default:
+		return reflect.Value{}, errors.Errorf("unsupported key type %s", t)
+	}
+
+	return key, nil
+}
+
+// sliceIndex converts a subscript to an index of the slice or array v.
+func sliceIndex(v reflect.Value, s string) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid index %q", s)
+	}
+	if i < 0 || i >= v.Len() {
+		return 0, errors.Errorf("index %d out of range [0, %d)", i, v.Len())
+	}
+
+	return i, nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package field
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coding-hui/common/errors"
+)
+
+type testMeta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testContainer struct {
+	Name  string         `json:"name"`
+	Env   map[string]int `json:"env"`
+	Ports []int32        `json:"ports"`
+}
+
+type testSpec struct {
+	Containers []testContainer        `json:"containers"`
+	Replicas   *int                   `json:"replicas,omitempty"`
+	Volumes    map[int]*testContainer `json:"volumes"`
+	Extra      map[string]interface{} `json:"extra"`
+	Hidden     string                 `json:"-"`
+	NoTag      bool
+}
+
+type testObject struct {
+	*testMeta `json:",inline"`
+
+	Spec testSpec `json:"spec"`
+}
+
+func newTestObject() *testObject {
+	return &testObject{
+		testMeta: &testMeta{Name: "web", Labels: map[string]string{"app.io/name": "web"}},
+		Spec: testSpec{
+			Containers: []testContainer{{Name: "nginx", Env: map[string]int{"FOO": 1}, Ports: []int32{80}}},
+			Volumes:    map[int]*testContainer{7: {Name: "data"}},
+			Extra:      map[string]interface{}{"nested": map[string]interface{}{"a": 1.0}},
+			Hidden:     "hidden",
+		},
+	}
+}
+
+func TestPathGet(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected interface{}
+		err      string
+	}{
+		{"name", "web", ""},
+		{"labels[app.io/name]", "web", ""},
+		{"spec.containers[0].name", "nginx", ""},
+		{"spec.containers[0].env[FOO]", 1, ""},
+		{"spec.containers[0].env.FOO", 1, ""},
+		{"spec.containers[0].ports[0]", int32(80), ""},
+		{"spec.volumes[7].name", "data", ""},
+		{"spec.extra.nested[a]", 1.0, ""},
+		{"spec.NoTag", false, ""},
+		{"spec.replicas", (*int)(nil), ""},
+		{"spec.Hidden", nil, "spec.Hidden: not found"},
+		{"spec.containers[1]", nil, "spec.containers[1]: index 1 out of range [0, 1)"},
+		{"spec.containers[x]", nil, `spec.containers[x]: invalid index "x"`},
+		{"spec.containers.name", nil, `spec.containers.name: cannot select "name" in []field.testContainer`},
+		{"spec.volumes[x]", nil, `spec.volumes[x]: invalid key "x" for int`},
+		{"spec.containers[0].env[BAR]", nil, "spec.containers[0].env[BAR]: not found"},
+		{"spec.replicas.value", nil, "spec.replicas.value: parent is nil"},
+	}
+
+	obj := newTestObject()
+	for i, tc := range testCases {
+		p, err := ParsePath(tc.path)
+		if err != nil {
+			t.Fatalf("[%d] Unexpected error: %v", i, err)
+		}
+
+		got, err := p.Get(obj)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("[%d] Expected error %q, got %v", i, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("[%d] Expected %#v, got %#v", i, tc.expected, got)
+		}
+	}
+
+	if _, err := NewPath("spec", "replicas", "value").Get(obj); !errors.Is(err, ErrNilParent) {
+		t.Errorf("Expected ErrNilParent, got %v", err)
+	}
+	if got, err := (*Path)(nil).Get(obj); err != nil || got != obj {
+		t.Errorf("Expected the object itself for an empty path, got %v, %v", got, err)
+	}
+}
+
+func TestPathSet(t *testing.T) {
+	testCases := []struct {
+		path  string
+		value interface{}
+		err   string
+	}{
+		{"name", "api", ""},
+		{"labels[tier]", "backend", ""},
+		{"spec.containers[0].env[BAR]", 2.0, ""},
+		{"spec.containers[0].ports[0]", 8080, ""},
+		{"spec.replicas", 3.0, ""},
+		{"spec.volumes[8].name", "logs", ""},
+		{"spec.extra.nested[b]", "x", ""},
+		{"spec.NoTag", true, ""},
+		{"spec.containers[0].name", nil, ""},
+		{"spec.containers[1].name", "x", "spec.containers[1]: index 1 out of range [0, 1)"},
+		{"spec.containers[0].name", 1, "cannot assign int to string"},
+		{"spec.unknown", 1, "spec.unknown: not found"},
+	}
+
+	obj := newTestObject()
+	for i, tc := range testCases {
+		p, err := ParsePath(tc.path)
+		if err != nil {
+			t.Fatalf("[%d] Unexpected error: %v", i, err)
+		}
+
+		err = p.Set(obj, tc.value)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("[%d] Expected error %q, got %v", i, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+
+	expected := newTestObject()
+	expected.Name = "api"
+	expected.Labels["tier"] = "backend"
+	expected.Spec.Containers[0].Name = ""
+	expected.Spec.Containers[0].Env["BAR"] = 2
+	expected.Spec.Containers[0].Ports[0] = 8080
+	replicas := 3
+	expected.Spec.Replicas = &replicas
+	expected.Spec.Volumes[8] = &testContainer{Name: "logs"}
+	expected.Spec.Extra["nested"].(map[string]interface{})["b"] = "x"
+	expected.Spec.NoTag = true
+	if !reflect.DeepEqual(obj, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, obj)
+	}
+
+	if err := NewPath("name").Set(*obj, "x"); err == nil {
+		t.Errorf("Expected an error for a non-pointer")
+	}
+
+	// like encoding/json, nil embedded pointers to unexported types can't be
+	// allocated
+	if err := NewPath("name").Set(&testObject{}, "x"); err == nil {
+		t.Errorf("Expected an error for a nil embedded pointer")
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantIndex []int
+		wantOK    bool
+	}{
+		{"name", []int{0, 0}, true},
+		{"labels", []int{0, 1}, true},
+		{"spec", []int{1}, true},
+		{"Spec", nil, false},
+		{"testMeta", nil, false},
+	}
+
+	typ := reflect.TypeOf(testObject{})
+	for _, tt := range tests {
+		fld, ok := JSONField(typ, tt.name)
+		if ok != tt.wantOK || ok && !reflect.DeepEqual(fld.Index, tt.wantIndex) {
+			t.Errorf("JSONField(%q): got %v %v, want %v %v", tt.name, fld.Index, ok, tt.wantIndex, tt.wantOK)
+		}
+	}
+
+	if _, ok := JSONField(reflect.TypeOf(testSpec{}), "Hidden"); ok {
+		t.Errorf("JSONField(Hidden): expected the field ignored by json to be skipped")
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+// IsValidCIDR tests that the argument is a valid IPv4 or IPv6 CIDR.
+func IsValidCIDR(value string) []string {
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		return []string{"must be a valid CIDR value, (e.g. 10.9.8.0/24 or 2001:db8::/64)"}
+	}
+	return nil
+}
+
+// IsValidIPv4CIDR tests that the argument is a valid IPv4 CIDR.
+func IsValidIPv4CIDR(fldPath *field.Path, value string) field.ErrorList {
+	var allErrors field.ErrorList
+	ip, _, err := net.ParseCIDR(value)
+	if err != nil || ip.To4() == nil {
+		allErrors = append(allErrors, field.Invalid(fldPath, value, "must be a valid IPv4 CIDR, (e.g. 10.9.8.0/24)"))
+	}
+	return allErrors
+}
+
+// IsValidIPv6CIDR tests that the argument is a valid IPv6 CIDR.
+func IsValidIPv6CIDR(fldPath *field.Path, value string) field.ErrorList {
+	var allErrors field.ErrorList
+	ip, _, err := net.ParseCIDR(value)
+	if err != nil || ip.To4() != nil {
+		allErrors = append(allErrors, field.Invalid(fldPath, value, "must be a valid IPv6 CIDR, (e.g. 2001:db8::/64)"))
+	}
+	return allErrors
+}
+
+// IsValidHostPort tests that the argument is a host and a port, where the
+// host is an IP address or a DNS (RFC 1123) subdomain, e.g. example.com:80
+// or [::1]:8080.
+func IsValidHostPort(value string) []string {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return []string{"must be a host and a port, (e.g. 'example.com:80' or '[::1]:8080')"}
+	}
+
+	var errs []string
+	if net.ParseIP(host) == nil {
+		if msgs := IsDNS1123Subdomain(host); len(msgs) != 0 {
+			errs = append(errs, prefixEach(msgs, "host part ")...)
+		}
+	}
+	if num, err := strconv.Atoi(port); err != nil {
+		errs = append(errs, "port part must be a number")
+	} else if msgs := IsValidPortNum(num); len(msgs) != 0 {
+		errs = append(errs, prefixEach(msgs, "port part ")...)
+	}
+	return errs
+}
+
+// IsValidURL tests that the argument is an absolute URL with a host. If
+// schemes are given, the scheme of the URL must be one of them.
+func IsValidURL(value string, schemes ...string) []string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return []string{"must be a valid absolute URL, (e.g. 'https://example.com/path')"}
+	}
+
+	if len(schemes) == 0 {
+		return nil
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("must have one of the schemes %s", strings.Join(schemes, ", "))}
+}
+
+// IsValidEmail tests that the argument is a valid email address, without
+// display name, e.g. user@example.com.
+func IsValidEmail(value string) []string {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Name != "" || addr.Address != value {
+		return []string{"must be a valid email address, (e.g. 'user@example.com')"}
+	}
+	return nil
+}
+
+const (
+	e164Fmt    string = `\+[1-9][0-9]{1,14}`
+	e164ErrMsg string = "must be a phone number in E.164 format"
+)
+
+var e164Regexp = regexp.MustCompile("^" + e164Fmt + "$")
+
+// IsValidE164 tests that the argument is a phone number in E.164 format.
+func IsValidE164(value string) []string {
+	if !e164Regexp.MatchString(value) {
+		return []string{RegexError(e164ErrMsg, e164Fmt, "+14155552671", "+8613800138000")}
+	}
+	return nil
+}
+
+const (
+	semverFmt string = `(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?`
+	semverErrMsg string = "must be a semantic version"
+)
+
+var semverRegexp = regexp.MustCompile("^" + semverFmt + "$")
+
+// IsValidSemver tests that the argument is a semantic version
+// (https://semver.org), without "v" prefix.
+func IsValidSemver(value string) []string {
+	if !semverRegexp.MatchString(value) {
+		return []string{RegexError(semverErrMsg, semverFmt, "1.2.3", "1.0.0-rc.1+build.5")}
+	}
+	return nil
+}
+
+// cronField is a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names of the values from min, if any
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}},
+	{name: "day of week", min: 0, max: 6, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// IsValidCron tests that the argument is a standard cron expression with
+// five fields (minute, hour, day of month, month and day of week), or one of
+// the descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight,
+// @hourly and @every <duration>.
+func IsValidCron(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "@") {
+		if cronDescriptors[value] {
+			return nil
+		}
+		if every := strings.TrimPrefix(value, "@every "); every != value {
+			if d, err := time.ParseDuration(strings.TrimSpace(every)); err == nil && d > 0 {
+				return nil
+			}
+			return []string{"@every must be followed by a positive duration, (e.g. '@every 1h30m')"}
+		}
+		return []string{fmt.Sprintf("unknown cron descriptor %q", value)}
+	}
+
+	parts := strings.Fields(value)
+	if len(parts) != len(cronFields) {
+		return []string{fmt.Sprintf("must be a cron expression with %d fields, (e.g. '*/5 * * * *'), found %d",
+			len(cronFields), len(parts))}
+	}
+
+	var errs []string
+	for i, part := range parts {
+		if msg := cronFields[i].validate(part); msg != "" {
+			errs = append(errs, cronFields[i].name+" "+msg)
+		}
+	}
+	return errs
+}
+
+// validate validates a field like "1-5/2,10" and returns the error message.
+func (f cronField) validate(value string) string {
+	for _, item := range strings.Split(value, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Sprintf("has an invalid step %q", step)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+
+		lo, hi, isRange := strings.Cut(rng, "-")
+		from, ok := f.value(lo)
+		if !ok {
+			return fmt.Sprintf("has an invalid value %q, must be between %d and %d", lo, f.min, f.max)
+		}
+		if !isRange {
+			continue
+		}
+		to, ok := f.value(hi)
+		if !ok {
+			return fmt.Sprintf("has an invalid value %q, must be between %d and %d", hi, f.min, f.max)
+		}
+		if from > to {
+			return fmt.Sprintf("has an invalid range %q", rng)
+		}
+	}
+	return ""
+}
+
+// value parses a value of the field, a number or a name.
+func (f cronField) value(s string) (int, bool) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, true
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, false
+	}
+	return n, true
+}
+
+// IsValidDuration tests that the argument is a duration accepted by
+// time.ParseDuration, e.g. 1h30m.
+func IsValidDuration(value string) []string {
+	if _, err := time.ParseDuration(value); err != nil {
+		return []string{"must be a valid duration, (e.g. '300ms', '1.5h' or '2h45m')"}
+	}
+	return nil
+}
+
+// IsValidGoTemplate tests that the argument is a valid Go text template.
+func IsValidGoTemplate(value string) []string {
+	if _, err := template.New("").Parse(value); err != nil {
+		return []string{fmt.Sprintf("must be a valid Go template: %v", err)}
+	}
+	return nil
+}
+
+// IsValidRFC3339 tests that the argument is a time in RFC 3339 format, with
+// optional fractional seconds.
+func IsValidRFC3339(value string) []string {
+	if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+		return []string{"must be a time in RFC 3339 format, (e.g. '2006-01-02T15:04:05Z07:00')"}
+	}
+	return nil
+}
+
+const (
+	quantityFmt    string = `[+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+)(?:[KMGTPE]i|[numkMGTPE]|[eE][+-]?[0-9]+)?`
+	quantityErrMsg string = "must be a resource quantity, a number with an optional SI or binary suffix"
+)
+
+var quantityRegexp = regexp.MustCompile("^" + quantityFmt + "$")
+
+// IsValidQuantity tests that the argument is a resource quantity, e.g. 500Mi,
+// 1.5, 100m or 1e3.
+func IsValidQuantity(value string) []string {
+	if !quantityRegexp.MatchString(value) {
+		return []string{RegexError(quantityErrMsg, quantityFmt, "500Mi", "0.5", "100m", "1e3")}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+func testFormat(t *testing.T, name string, fn func(string) []string, goodValues, badValues []string) {
+	t.Helper()
+
+	for _, val := range goodValues {
+		if msgs := fn(val); len(msgs) != 0 {
+			t.Errorf("%s: expected true for %q: %v", name, val, msgs)
+		}
+	}
+	for _, val := range badValues {
+		if msgs := fn(val); len(msgs) == 0 {
+			t.Errorf("%s: expected false for %q", name, val)
+		}
+	}
+}
+
+func TestIsValidCIDR(t *testing.T) {
+	testFormat(t, "IsValidCIDR", IsValidCIDR,
+		[]string{"10.0.0.0/8", "192.168.1.1/32", "0.0.0.0/0", "2001:db8::/64", "::/0", "::1/128"},
+		[]string{"", "10.0.0.0", "10.0.0.0/33", "2001:db8::/129", "10.0.0/8", "a.b.c.d/8", "10.0.0.0/-1"},
+	)
+
+	for _, val := range []string{"10.0.0.0/8", "2001:db8::/64", "10.0.0.0"} {
+		v4 := IsValidIPv4CIDR(field.NewPath("cidr"), val)
+		v6 := IsValidIPv6CIDR(field.NewPath("cidr"), val)
+		switch val {
+		case "10.0.0.0/8":
+			if len(v4) != 0 || len(v6) == 0 {
+				t.Errorf("expected %q to be only a valid IPv4 CIDR: %v, %v", val, v4, v6)
+			}
+		case "2001:db8::/64":
+			if len(v4) == 0 || len(v6) != 0 {
+				t.Errorf("expected %q to be only a valid IPv6 CIDR: %v, %v", val, v4, v6)
+			}
+		default:
+			if len(v4) == 0 || len(v6) == 0 {
+				t.Errorf("expected %q to be an invalid CIDR: %v, %v", val, v4, v6)
+			}
+		}
+	}
+}
+
+func TestIsValidHostPort(t *testing.T) {
+	testFormat(t, "IsValidHostPort", IsValidHostPort,
+		[]string{"example.com:80", "localhost:8080", "10.0.0.1:443", "[::1]:65535", "a.b.c:1"},
+		[]string{"", "example.com", "example.com:", ":80", "example.com:0", "example.com:65536",
+			"example.com:http", "Example.com:80", "-a.com:80", "::1:80"},
+	)
+}
+
+func TestIsValidURL(t *testing.T) {
+	testFormat(t, "IsValidURL", func(value string) []string { return IsValidURL(value) },
+		[]string{"https://example.com", "http://localhost:8080/path?q=1", "ftp://10.0.0.1/file", "HTTPS://EXAMPLE.COM"},
+		[]string{"", "example.com", "/path", "https://", "mailto:user@example.com", "http://[::1"},
+	)
+	testFormat(t, "IsValidURL(https)", func(value string) []string { return IsValidURL(value, "https", "wss") },
+		[]string{"https://example.com", "HTTPS://example.com", "wss://example.com/socket"},
+		[]string{"http://example.com", "ftp://example.com"},
+	)
+}
+
+func TestIsValidEmail(t *testing.T) {
+	testFormat(t, "IsValidEmail", IsValidEmail,
+		[]string{"user@example.com", "first.last+tag@sub.example.io", "u@localhost"},
+		[]string{"", "user", "user@", "@example.com", "User <user@example.com>", " user@example.com", "a b@example.com"},
+	)
+}
+
+func TestIsValidE164(t *testing.T) {
+	testFormat(t, "IsValidE164", IsValidE164,
+		[]string{"+14155552671", "+8613800138000", "+12"},
+		[]string{"", "14155552671", "+0123456", "+1", "+1234567890123456", "+1 415 555 2671", "+1-415"},
+	)
+}
+
+func TestIsValidSemver(t *testing.T) {
+	testFormat(t, "IsValidSemver", IsValidSemver,
+		[]string{"0.0.0", "1.2.3", "10.20.30", "1.0.0-alpha", "1.0.0-rc.1+build.5", "1.0.0+20130313144700", "1.0.0-0.3.7"},
+		[]string{"", "1", "1.2", "v1.2.3", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3.4"},
+	)
+}
+
+func TestIsValidCron(t *testing.T) {
+	testFormat(t, "IsValidCron", IsValidCron,
+		[]string{
+			"* * * * *", "*/5 * * * *", "0 0 1 1 0", "59 23 31 12 6", "0 9-17/2 * * mon-fri",
+			"0,15,30,45 * * JAN,Jul *", "5/10 * * * *", "@daily", "@every 1h30m",
+		},
+		[]string{
+			"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+			"* * * * 7", "*/0 * * * *", "*/x * * * *", "5-1 * * * *", "* * * foo *", "@often",
+			"@every", "@every -1h", "1,,2 * * * *",
+		},
+	)
+
+	msgs := IsValidCron("60 * * * *")
+	if len(msgs) != 1 || msgs[0] != `minute has an invalid value "60", must be between 0 and 59` {
+		t.Errorf("unexpected messages: %v", msgs)
+	}
+}
+
+func TestIsValidDuration(t *testing.T) {
+	testFormat(t, "IsValidDuration", IsValidDuration,
+		[]string{"0", "300ms", "1.5h", "2h45m", "-1s"},
+		[]string{"", "1", "1d", "h", "1 h"},
+	)
+}
+
+func TestIsValidGoTemplate(t *testing.T) {
+	testFormat(t, "IsValidGoTemplate", IsValidGoTemplate,
+		[]string{"", "plain text", "Hello {{ .Name }}", "{{ range .Items }}{{ . }}{{ end }}"},
+		[]string{"{{ .Name }", "{{ range .Items }}", "{{ end }}", "{{ unknownFunc . }}"},
+	)
+}
+
+func TestIsValidRFC3339(t *testing.T) {
+	testFormat(t, "IsValidRFC3339", IsValidRFC3339,
+		[]string{"2006-01-02T15:04:05Z", "2006-01-02T15:04:05+08:00", "2006-01-02T15:04:05.999999999Z"},
+		[]string{"", "2006-01-02", "2006-01-02 15:04:05Z", "2006-01-02T15:04:05", "2006-13-02T15:04:05Z"},
+	)
+}
+
+func TestIsValidQuantity(t *testing.T) {
+	testFormat(t, "IsValidQuantity", IsValidQuantity,
+		[]string{"0", "1", "500Mi", "1Gi", "0.5", ".5", "1.", "100m", "1k", "2M", "1e3", "1E-3", "+1", "-1Ki"},
+		[]string{"", "Mi", "1MB", "1mi", "1.2.3", "1 Gi", "1Ki2", "e3", "1e"},
+	)
+}
@@ -9,8 +9,8 @@ import (
 	"net"
 	"regexp"
 	"strings"
-	"unicode"
 
+	"github.com/coding-hui/common/errors"
 	"github.com/coding-hui/common/validation/field"
 )
 
@@ -246,62 +246,17 @@ const (
 	maxPassLength = 16
 )
 
-// IsValidPassword validate password.
+// IsValidPassword validate password against the DefaultPasswordPolicy.
 func IsValidPassword(password string) error {
-	var hasUpper bool
-	var hasLower bool
-	var hasNumber bool
-	var hasSpecial bool
-	var passLen int
-	var errorString string
-
-	for _, ch := range password {
-		switch {
-		case unicode.IsNumber(ch):
-			hasNumber = true
-			passLen++
-		case unicode.IsUpper(ch):
-			hasUpper = true
-			passLen++
-		case unicode.IsLower(ch):
-			hasLower = true
-			passLen++
-		case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
-			hasSpecial = true
-			passLen++
-		case ch == ' ':
-			passLen++
-		}
-	}
-
-	appendError := func(err string) {
-		if len(strings.TrimSpace(errorString)) != 0 {
-			errorString += ", " + err
-		} else {
-			errorString = err
-		}
-	}
-	if !hasLower {
-		appendError("lowercase letter missing")
-	}
-	if !hasUpper {
-		appendError("uppercase letter missing")
-	}
-	if !hasNumber {
-		appendError("at least one numeric character required")
-	}
-	if !hasSpecial {
-		appendError("special character missing")
-	}
-	if !(minPassLength <= passLen && passLen <= maxPassLength) {
-		appendError(
-			fmt.Sprintf("password length must be between %d to %d characters long", minPassLength, maxPassLength),
-		)
+	errs := DefaultPasswordPolicy.Validate(nil, password)
+	if len(errs) == 0 {
+		return nil
 	}
 
-	if len(errorString) != 0 {
-		return fmt.Errorf(errorString)
+	details := make([]string, 0, len(errs))
+	for _, err := range errs {
+		details = append(details, err.Detail)
 	}
 
-	return nil
+	return errors.New(strings.Join(details, ", "))
 }
@@ -0,0 +1,234 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	english "github.com/go-playground/locales/en"
+	chinese "github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/translations/en"
+	"github.com/go-playground/validator/v10/translations/zh"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/validation/field"
+)
+
+// Locales supported out of the box.
+const (
+	LocaleEnglish = "en"
+	LocaleChinese = "zh"
+)
+
+// DefaultLocale is the locale of Validate.
+const DefaultLocale = LocaleEnglish
+
+// Locale is a language the messages of a Validator can be translated to.
+type Locale struct {
+	// Translator implements the plural rules and the number and date
+	// formats of the language.
+	Translator locales.Translator
+
+	// RegisterDefaultTranslations registers the messages of the built-in
+	// tags of the validator, such as required or max. It can be nil.
+	RegisterDefaultTranslations func(v *validator.Validate, trans ut.Translator) error
+
+	// Translations are the messages of the custom tags, keyed by tag. {0} is
+	// replaced by the field name and {1} by the field value.
+	Translations map[string]string
+}
+
+var (
+	localeMux     sync.RWMutex
+	localeConfigs = map[string]*Locale{
+		LocaleEnglish: {
+			Translator:                  english.New(),
+			RegisterDefaultTranslations: en.RegisterDefaultTranslations,
+			Translations: map[string]string{
+				"dir":         "{0} must point to an existing directory, but found '{1}'",
+				"file":        "{0} must point to an existing file, but found '{1}'",
+				"description": fmt.Sprintf("must be less than %d", maxDescriptionLength),
+				"name":        "is not a invalid name",
+			},
+		},
+		LocaleChinese: {
+			Translator:                  chinese.New(),
+			RegisterDefaultTranslations: zh.RegisterDefaultTranslations,
+			Translations: map[string]string{
+				"dir":         "{0}必须指向一个已存在的目录，但实际为'{1}'",
+				"file":        "{0}必须指向一个已存在的文件，但实际为'{1}'",
+				"description": fmt.Sprintf("长度必须小于%d", maxDescriptionLength),
+				"name":        "不是一个合法的名称",
+			},
+		},
+	}
+)
+
+// RegisterLocale adds a locale, or replaces the locale with the same name.
+// It applies to the validators which haven't used the locale yet: the
+// translator of a locale is created on first use.
+func RegisterLocale(name string, locale Locale) {
+	translations := make(map[string]string, len(locale.Translations))
+	for tag, translation := range locale.Translations {
+		translations[tag] = translation
+	}
+	locale.Translations = translations
+
+	localeMux.Lock()
+	defer localeMux.Unlock()
+
+	localeConfigs[normalizeLocale(name)] = &locale
+}
+
+// RegisterTranslation registers the message of a tag in a locale. It applies
+// to the validators which haven't used the locale yet; use
+// Validator.RegisterTranslation for an existing validator.
+func RegisterTranslation(locale, tag, translation string) error {
+	localeMux.Lock()
+	defer localeMux.Unlock()
+
+	l, ok := localeConfigs[normalizeLocale(locale)]
+	if !ok {
+		return errors.Errorf("unsupported locale %q", locale)
+	}
+	l.Translations[tag] = translation
+
+	return nil
+}
+
+// SupportedLocales returns the names of the registered locales.
+func SupportedLocales() []string {
+	localeMux.RLock()
+	defer localeMux.RUnlock()
+
+	names := make([]string, 0, len(localeConfigs))
+	for name := range localeConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// newTranslator creates the translator of the locale for v. localeMux must
+// be held.
+func newTranslator(v *validator.Validate, l *Locale) (ut.Translator, error) {
+	uni := ut.New(l.Translator, l.Translator)
+	trans, _ := uni.GetTranslator(l.Translator.Locale())
+
+	if l.RegisterDefaultTranslations != nil {
+		if err := l.RegisterDefaultTranslations(v, trans); err != nil {
+			return nil, err
+		}
+	}
+	for tag, translation := range l.Translations {
+		if err := v.RegisterTranslation(tag, trans, registrationFunc(tag, translation), translateFunc); err != nil {
+			return nil, err
+		}
+	}
+
+	return trans, nil
+}
+
+// RegisterTranslation registers the message of a tag in a locale of v.
+func (v *Validator) RegisterTranslation(locale, tag, translation string) error {
+	return v.engine.RegisterTranslation(locale, tag, translation)
+}
+
+// ValidateLocale is like Validate, but the details of the errors are
+// translated to the first supported of the given locales.
+func (v *Validator) ValidateLocale(preferred ...string) field.ErrorList {
+	return v.engine.Validate(v.data, preferred...)
+}
+
+// ValidateRequest is like Validate, but the details of the errors are
+// translated to the preferred supported language of the Accept-Language
+// header of r.
+func (v *Validator) ValidateRequest(r *http.Request) field.ErrorList {
+	return v.ValidateLocale(errors.AcceptLanguages(r)...)
+}
+
+// RegisterTranslation registers the message of a tag in a locale of e.
+// It must not be called concurrently with the validation methods.
+func (e *Engine) RegisterTranslation(locale, tag, translation string) error {
+	trans, err := e.translatorOf(normalizeLocale(locale))
+	if err != nil {
+		return err
+	}
+	if trans == nil {
+		return errors.Errorf("unsupported locale %q", locale)
+	}
+
+	e.transMux.Lock()
+	defer e.transMux.Unlock()
+
+	return e.val.RegisterTranslation(tag, trans, registrationFunc(tag, translation), translateFunc)
+}
+
+// translator returns the translator of the first supported locale, trying
+// the language of regional locales such as zh-CN too, or the translator of
+// the default locale.
+func (e *Engine) translator(preferred ...string) ut.Translator {
+	for _, locale := range preferred {
+		locale = normalizeLocale(locale)
+		if trans, _ := e.translatorOf(locale); trans != nil {
+			return trans
+		}
+		if i := strings.Index(locale, "-"); i > 0 {
+			if trans, _ := e.translatorOf(locale[:i]); trans != nil {
+				return trans
+			}
+		}
+	}
+
+	trans, _ := e.translatorOf(DefaultLocale)
+
+	return trans
+}
+
+// translatorOf returns the translator of a registered locale, which is
+// created on first use, or nil if the locale isn't registered.
+func (e *Engine) translatorOf(name string) (ut.Translator, error) {
+	e.transMux.RLock()
+	trans, ok := e.translators[name]
+	e.transMux.RUnlock()
+	if ok {
+		return trans, nil
+	}
+
+	e.transMux.Lock()
+	defer e.transMux.Unlock()
+
+	if trans, ok := e.translators[name]; ok {
+		return trans, nil
+	}
+
+	localeMux.RLock()
+	defer localeMux.RUnlock()
+
+	l, ok := localeConfigs[name]
+	if !ok {
+		return nil, nil
+	}
+	trans, err := newTranslator(e.val, l)
+	if err != nil {
+		return nil, errors.Errorf("locale %s: %v", name, err)
+	}
+	e.translators[name] = trans
+
+	return trans, nil
+}
+
+// normalizeLocale converts locales like zh_CN to zh-cn.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type localeStruct struct {
+	Name string `validate:"required"`
+	Dir  string `validate:"omitempty,dir"`
+}
+
+func TestValidateLocale(t *testing.T) {
+	data := &localeStruct{Dir: "/nonexistingdirectoryinroot"}
+
+	tests := []struct {
+		locales []string
+		want    []string
+	}{
+		{nil, []string{
+			"Name is a required field",
+			"Dir must point to an existing directory, but found '/nonexistingdirectoryinroot'",
+		}},
+		{[]string{"zh"}, []string{
+			"Name为必填字段",
+			"Dir必须指向一个已存在的目录，但实际为'/nonexistingdirectoryinroot'",
+		}},
+		{[]string{"fr-FR", "zh_CN"}, []string{
+			"Name为必填字段",
+			"Dir必须指向一个已存在的目录，但实际为'/nonexistingdirectoryinroot'",
+		}},
+		{[]string{"fr"}, []string{
+			"Name is a required field",
+			"Dir must point to an existing directory, but found '/nonexistingdirectoryinroot'",
+		}},
+	}
+
+	val := NewValidator(data)
+	for _, test := range tests {
+		errs := val.ValidateLocale(test.locales...)
+		if !assert.Len(t, errs, len(test.want), "locales %v", test.locales) {
+			continue
+		}
+		for i, want := range test.want {
+			assert.Equal(t, want, errs[i].Detail, "locales %v", test.locales)
+		}
+		assert.Equal(t, "localeStruct.Dir", errs[1].Field)
+		assert.Equal(t, data.Dir, errs[1].BadValue)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en;q=0.5, zh-CN")
+	errs := val.ValidateRequest(req)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "Name为必填字段", errs[0].Detail)
+	}
+}
+
+func TestRegisterTranslation(t *testing.T) {
+	assert.Error(t, RegisterTranslation("xx", "dir", "{0}"))
+	assert.Contains(t, SupportedLocales(), LocaleChinese)
+
+	type port struct {
+		Port int `validate:"even"`
+	}
+	val := NewValidator(&port{Port: 1})
+	_ = val.engine.RegisterValidation("even", func(fl validator.FieldLevel) bool { return fl.Field().Int()%2 == 0 })
+	assert.NoError(t, val.RegisterTranslation("zh", "even", "{0}必须是偶数"))
+	assert.Error(t, val.RegisterTranslation("xx", "even", "{0}"))
+
+	errs := val.ValidateLocale("zh")
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "Port必须是偶数", errs[0].Detail)
+	}
+}
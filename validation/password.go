@@ -0,0 +1,247 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/util/sets"
+	"github.com/coding-hui/common/validation/field"
+)
+
+// passwordMask is the value of the errors of passwords, which are never
+// reported.
+const passwordMask = "[REDACTED]"
+
+// minUserInputLength is the length under which the user inputs are not
+// searched in passwords, to avoid false positives.
+const minUserInputLength = 3
+
+// PasswordPolicy is a set of requirements for passwords.
+type PasswordPolicy struct {
+	// MinLength and MaxLength bound the number of characters: letters,
+	// digits, punctuation, symbols and spaces; other characters, e.g.
+	// control characters, aren't counted. Zero means no bound.
+	MinLength int
+	MaxLength int
+
+	// RequireUpper, RequireLower, RequireDigit and RequireSpecial require at
+	// least one character of the class. Special characters are punctuation
+	// and symbols.
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+
+	// MaxRepeats is the maximum number of times a character can be repeated
+	// in a row, e.g. 2 rejects "aaa". Zero means no limit.
+	MaxRepeats int
+
+	// Blocklist contains the lower-case passwords which are too common to be
+	// used, see LoadPasswordBlocklist.
+	Blocklist sets.String
+
+	// MinEntropy is the minimum entropy in bits, see PasswordEntropy. Zero
+	// means no minimum.
+	MinEntropy float64
+}
+
+// DefaultPasswordPolicy is the policy of IsValidPassword.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:      minPassLength,
+	MaxLength:      maxPassLength,
+	RequireUpper:   true,
+	RequireLower:   true,
+	RequireDigit:   true,
+	RequireSpecial: true,
+}
+
+// Validate validates password against the policy and returns every
+// violation. The user inputs, e.g. the username and the email, must not be
+// contained in the password, ignoring case; the local part of emails is
+// searched too. The password itself is never part of the errors.
+func (p PasswordPolicy) Validate(fldPath *field.Path, password string, userInputs ...string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	invalid := func(detail string) {
+		allErrs = append(allErrs, field.Invalid(fldPath, passwordMask, detail))
+	}
+
+	classes := passwordClassesOf(password)
+	if p.RequireLower && !classes.lower {
+		invalid("lowercase letter missing")
+	}
+	if p.RequireUpper && !classes.upper {
+		invalid("uppercase letter missing")
+	}
+	if p.RequireDigit && !classes.digit {
+		invalid("at least one numeric character required")
+	}
+	if p.RequireSpecial && !classes.special {
+		invalid("special character missing")
+	}
+
+	length := classes.length
+	switch {
+	case p.MinLength > 0 && p.MaxLength > 0 && (length < p.MinLength || length > p.MaxLength):
+		invalid(fmt.Sprintf("password length must be between %d to %d characters long", p.MinLength, p.MaxLength))
+	case p.MinLength > 0 && length < p.MinLength:
+		invalid(fmt.Sprintf("password length must be at least %d characters long", p.MinLength))
+	case p.MaxLength > 0 && length > p.MaxLength:
+		invalid(fmt.Sprintf("password length must be at most %d characters long", p.MaxLength))
+	}
+
+	if p.MaxRepeats > 0 && maxRepeats(password) > p.MaxRepeats {
+		invalid(fmt.Sprintf("must not repeat a character more than %d times in a row", p.MaxRepeats))
+	}
+
+	lower := strings.ToLower(password)
+	if p.Blocklist.Has(lower) {
+		invalid("is too common")
+	}
+	if containsUserInput(lower, userInputs) {
+		invalid("must not contain the username or email")
+	}
+
+	if p.MinEntropy > 0 {
+		if entropy := PasswordEntropy(password); entropy < p.MinEntropy {
+			invalid(fmt.Sprintf("is too easy to guess: %.0f bits of entropy, at least %.0f required", entropy, p.MinEntropy))
+		}
+	}
+
+	return allErrs
+}
+
+// passwordClasses are the classes of the characters of a password.
+type passwordClasses struct {
+	lower, upper, digit, special, space, other bool
+
+	// length is the number of characters, other characters excluded.
+	length int
+}
+
+func passwordClassesOf(password string) passwordClasses {
+	var c passwordClasses
+	for _, ch := range password {
+		switch {
+		case unicode.IsNumber(ch):
+			c.digit = true
+		case unicode.IsUpper(ch):
+			c.upper = true
+		case unicode.IsLower(ch):
+			c.lower = true
+		case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
+			c.special = true
+		case ch == ' ':
+			c.space = true
+		default:
+			c.other = true
+			continue
+		}
+		c.length++
+	}
+
+	return c
+}
+
+// maxRepeats returns the maximum number of times a character is repeated in
+// a row.
+func maxRepeats(password string) int {
+	result, count := 0, 0
+	var prev rune
+	for i, ch := range password {
+		if i > 0 && ch == prev {
+			count++
+		} else {
+			count = 1
+		}
+		if count > result {
+			result = count
+		}
+		prev = ch
+	}
+
+	return result
+}
+
+// containsUserInput reports whether the lower-case password contains one of
+// the user inputs.
+func containsUserInput(password string, userInputs []string) bool {
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		candidates := []string{input}
+		if i := strings.LastIndex(input, "@"); i > 0 {
+			candidates = append(candidates, input[:i])
+		}
+
+		for _, candidate := range candidates {
+			if len(candidate) >= minUserInputLength && strings.Contains(password, candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// PasswordEntropy estimates the entropy of a password in bits, as its
+// length times the binary logarithm of the size of the pool of its
+// character classes, e.g. 26 for lower-case letters only.
+func PasswordEntropy(password string) float64 {
+	c := passwordClassesOf(password)
+
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{
+		{c.lower, 26},
+		{c.upper, 26},
+		{c.digit, 10},
+		{c.special, 33},
+		{c.space, 1},
+		{c.other, 100},
+	} {
+		if class.present {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(utf8.RuneCountInString(password)) * math.Log2(float64(pool))
+}
+
+// LoadPasswordBlocklist loads a blocklist of passwords from a file with one
+// password per line. Empty lines and lines starting with # are ignored.
+func LoadPasswordBlocklist(path string) (sets.String, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open password blocklist %s", path)
+	}
+	defer file.Close()
+
+	blocklist := sets.NewString()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist.Insert(strings.ToLower(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "read password blocklist %s", path)
+	}
+
+	return blocklist, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coding-hui/common/util/sets"
+	"github.com/coding-hui/common/validation/field"
+)
+
+func detailsOf(errs field.ErrorList) []string {
+	details := []string{}
+	for _, err := range errs {
+		details = append(details, err.Detail)
+	}
+
+	return details
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireDigit: true,
+		MaxRepeats:   2,
+		Blocklist:    sets.NewString("password123!"),
+		MinEntropy:   50,
+	}
+
+	tests := []struct {
+		password   string
+		userInputs []string
+		want       []string
+	}{
+		{"Correct-Horse-7", nil, []string{}},
+		{"Correct-Horse-7", []string{"horse", "nobody@example.com"}, []string{"must not contain the username or email"}},
+		{"Correct-Horse-7", []string{"lk", "lk@example.com"}, []string{}},
+		{"Mycorrecthorse7", []string{"alice", "CorrectHorse@example.com"}, []string{"must not contain the username or email"}},
+		{"short", nil, []string{
+			"uppercase letter missing",
+			"at least one numeric character required",
+			"password length must be at least 10 characters long",
+			"is too easy to guess: 24 bits of entropy, at least 50 required",
+		}},
+		{"Aaaa-bbbb-1234", nil, []string{"must not repeat a character more than 2 times in a row"}},
+		{"PassWord123!", nil, []string{"is too common"}},
+	}
+
+	for i, test := range tests {
+		errs := policy.Validate(field.NewPath("password"), test.password, test.userInputs...)
+		assert.Equal(t, test.want, detailsOf(errs), "test %d", i+1)
+		for _, err := range errs {
+			assert.Equal(t, "password", err.Field)
+			assert.NotContains(t, err.Error(), test.password)
+		}
+	}
+}
+
+func TestIsValidPassword(t *testing.T) {
+	assert.NoError(t, IsValidPassword("Passw0rd!"))
+
+	err := IsValidPassword("password")
+	if assert.Error(t, err) {
+		assert.Equal(t, "uppercase letter missing, at least one numeric character required, special character missing", err.Error())
+	}
+
+	err = IsValidPassword("P@ssw0rdP@ssw0rd!")
+	if assert.Error(t, err) {
+		assert.Equal(t, "password length must be between 8 to 16 characters long", err.Error())
+	}
+
+	// characters are counted, not bytes, and control characters aren't
+	assert.NoError(t, IsValidPassword("Pässwörd1!"))
+	assert.NoError(t, IsValidPassword("Pässwörd1!ÄÖÜäöü\t"))
+	err = IsValidPassword("Pä1!\tö")
+	if assert.Error(t, err) {
+		assert.Equal(t, "password length must be between 8 to 16 characters long", err.Error())
+	}
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	assert.Equal(t, 0.0, PasswordEntropy(""))
+	assert.InDelta(t, 4*math.Log2(26), PasswordEntropy("abcd"), 1e-9)
+	assert.InDelta(t, 8*math.Log2(26+26+10+33), PasswordEntropy("Abcd-123"), 1e-9)
+	assert.Less(t, PasswordEntropy("abcdefgh"), PasswordEntropy("Abcdefg1"))
+}
+
+func TestLoadPasswordBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# common passwords\n123456\n\n  Qwerty  \n"), 0o600))
+
+	blocklist, err := LoadPasswordBlocklist(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"123456", "qwerty"}, blocklist.List())
+	}
+
+	_, err = LoadPasswordBlocklist(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,851 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/coding-hui/common/errors"
+	"github.com/coding-hui/common/validation/field"
+)
+
+// RulesTag is the struct tag of the declarative rules of a field.
+//
+// The tag holds rules separated by semicolons. A rule is either `required`,
+// which requires the field to be set, or an expression which must be true,
+// optionally followed by `if` and a condition:
+//
+//	type Autoscaling struct {
+//	        MinReplicas int `json:"minReplicas" rules:"self <= maxReplicas"`
+//	        MaxReplicas int `json:"maxReplicas" rules:"self > 0; self <= 100 if !has(burst)"`
+//	        Burst       bool `json:"burst"`
+//	}
+//
+//	type TLS struct {
+//	        Enabled  bool   `json:"enabled"`
+//	        CertFile string `json:"certFile" rules:"required if enabled"`
+//	}
+//
+// Expressions support the literals true, false, null, numbers and quoted
+// strings, the operators ||, &&, !, ==, !=, <, <=, > and >=, parentheses, and
+// the functions has(x), which reports whether x is set, and len(x). A field
+// is set if it's non-zero, or if it's a non-nil pointer, even to a zero
+// value. Fields are referenced by their json path relative to the struct of
+// the tagged field, e.g. tls.enabled, and self is the tagged field itself.
+//
+// The rules are evaluated by the validation methods of Engine.
+const RulesTag = "rules"
+
+// Rule is a declarative cross-field rule of a struct, the programmatic
+// counterpart of the RulesTag.
+type Rule struct {
+	// Field is the json path, relative to the struct, of the field the rule
+	// applies to, and the errors are reported on. Empty means the struct.
+	Field string
+
+	// Required requires the field to be set, i.e. to be non-zero or a non-nil
+	// pointer.
+	Required bool
+
+	// Expr, if not empty, is an expression which must be true.
+	Expr string
+
+	// When, if not empty, is the condition of the rule: the rule is ignored
+	// if it's false.
+	When string
+
+	// Message is the detail of the errors. It defaults to a description of
+	// the rule.
+	Message string
+}
+
+// RegisterRules registers rules for the struct type of typ, in addition to
+// the rules of its tags. The rules are evaluated by Validate and
+// ValidatePartial. It must not be called concurrently with the validation
+// methods.
+func (e *Engine) RegisterRules(typ interface{}, rules ...Rule) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return errors.Errorf("rules can only be registered for structs, got %T", typ)
+	}
+
+	compiled := make([]*compiledRule, 0, len(rules))
+	for _, r := range rules {
+		c, err := compileRule(r)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c)
+	}
+
+	if e.rules == nil {
+		e.rules = map[reflect.Type][]*compiledRule{}
+	}
+	e.rules[t] = append(e.rules[t], compiled...)
+
+	return nil
+}
+
+// compiledRule is a parsed Rule.
+type compiledRule struct {
+	field   []string
+	expr    node
+	when    node
+	rule    Rule
+	invalid error
+}
+
+func compileRule(r Rule) (*compiledRule, error) {
+	c := &compiledRule{rule: r}
+	if r.Field != "" {
+		c.field = strings.Split(r.Field, ".")
+	}
+	if !r.Required && r.Expr == "" {
+		return nil, errors.Errorf("rule of %q: either Required or Expr must be set", r.Field)
+	}
+
+	var err error
+	if r.Expr != "" {
+		if c.expr, err = parseExpr(r.Expr); err != nil {
+			return nil, errors.Wrapf(err, "rule of %q", r.Field)
+		}
+	}
+	if r.When != "" {
+		if c.when, err = parseExpr(r.When); err != nil {
+			return nil, errors.Wrapf(err, "rule of %q", r.Field)
+		}
+	}
+
+	return c, nil
+}
+
+// tagRules caches the rules of the tags of struct types.
+var tagRules sync.Map // map[reflect.Type][]*compiledRule
+
+// rulesOfTags returns the rules of the tags of the fields of t.
+func rulesOfTags(t reflect.Type) []*compiledRule {
+	if cached, ok := tagRules.Load(t); ok {
+		return cached.([]*compiledRule)
+	}
+
+	rules := []*compiledRule{}
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		tag, ok := fld.Tag.Lookup(RulesTag)
+		if !ok || fld.PkgPath != "" {
+			continue
+		}
+
+		name := jsonTagName(fld)
+		if name == "" {
+			name = fld.Name
+		}
+
+		parsed, err := parseRules(tag)
+		if err != nil {
+			rules = append(rules, &compiledRule{field: []string{name}, invalid: err})
+			continue
+		}
+		for _, r := range parsed {
+			r.Field = name
+			c, _ := compileRule(r) // already parsed
+			rules = append(rules, c)
+		}
+	}
+
+	tagRules.Store(t, rules)
+
+	return rules
+}
+
+// evaluateRules evaluates the rules of v and of the values it contains.
+func (e *Engine) evaluateRules(v reflect.Value, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return allErrs
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		rules := rulesOfTags(v.Type())
+		rules = append(rules[:len(rules):len(rules)], e.rules[v.Type()]...)
+		for _, r := range rules {
+			for _, err := range r.evaluate(v, path) {
+				allErrs = append(allErrs, err.WithOrigin(RulesTag))
+			}
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			fld := v.Type().Field(i)
+			if fld.PkgPath != "" {
+				continue
+			}
+			name := jsonTagName(fld)
+			if name == "" {
+				name = fld.Name
+			}
+			allErrs = append(allErrs, e.evaluateRules(v.Field(i), childPath(path, name))...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			allErrs = append(allErrs, e.evaluateRules(v.Index(i), path.Index(i))...)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			allErrs = append(allErrs, e.evaluateRules(iter.Value(), path.Key(fmt.Sprint(iter.Key().Interface())))...)
+		}
+	}
+
+	return allErrs
+}
+
+func childPath(path *field.Path, name string) *field.Path {
+	if path == nil {
+		return field.NewPath(name)
+	}
+
+	return path.Child(name)
+}
+
+// evaluate evaluates the rule against the struct v at path.
+func (r *compiledRule) evaluate(v reflect.Value, path *field.Path) field.ErrorList {
+	fldPath := path
+	for _, name := range r.field {
+		fldPath = childPath(fldPath, name)
+	}
+	if fldPath == nil {
+		fldPath = field.NewPath("")
+	}
+
+	if r.invalid != nil {
+		return field.ErrorList{field.InternalError(fldPath, r.invalid)}
+	}
+
+	self := v
+	if len(r.field) > 0 {
+		var err error
+		if self, err = resolveField(v, r.field); err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+	}
+	ctx := &evalContext{parent: v, self: self}
+
+	if r.when != nil {
+		ok, err := evalBool(r.when, ctx)
+		if err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	if r.rule.Required && !isSetField(self) {
+		detail := r.rule.Message
+		if detail == "" && r.rule.When != "" {
+			detail = fmt.Sprintf("must be set when %s", r.rule.When)
+		}
+		return field.ErrorList{field.Required(fldPath, detail)}
+	}
+
+	if r.expr != nil {
+		ok, err := evalBool(r.expr, ctx)
+		if err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+		if !ok {
+			detail := r.rule.Message
+			if detail == "" {
+				detail = fmt.Sprintf("must satisfy %s", r.rule.Expr)
+			}
+			var value interface{}
+			if self.IsValid() && self.CanInterface() {
+				value = self.Interface()
+			}
+			return field.ErrorList{field.Invalid(fldPath, value, detail)}
+		}
+	}
+
+	return nil
+}
+
+// parseRules parses the rules of a RulesTag.
+func parseRules(tag string) ([]Rule, error) {
+	p := &parser{src: tag}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	rules := []Rule{}
+	for p.peek().kind != tokenEOF {
+		start := p.peek().pos
+
+		var r Rule
+		if p.peek().kind == tokenIdent && p.peek().text == "required" {
+			p.next()
+			r.Required = true
+		} else {
+			if _, err := p.parseOr(); err != nil {
+				return nil, err
+			}
+			r.Expr = strings.TrimSpace(tag[start:p.peek().pos])
+		}
+
+		if p.peek().kind == tokenIdent && p.peek().text == "if" {
+			p.next()
+			whenStart := p.peek().pos
+			if _, err := p.parseOr(); err != nil {
+				return nil, err
+			}
+			r.When = strings.TrimSpace(tag[whenStart:p.peek().pos])
+		}
+
+		switch t := p.next(); t.kind {
+		case tokenEOF:
+			rules = append(rules, r)
+			return rules, nil
+		case tokenSemicolon:
+			rules = append(rules, r)
+		default:
+			return nil, errors.Errorf("unexpected %q at position %d", t.text, t.pos)
+		}
+	}
+
+	return rules, nil
+}
+
+// parseExpr parses an expression.
+func parseExpr(src string) (node, error) {
+	p := &parser{src: src}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errors.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+
+	return n, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenSemicolon
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// parser is a recursive descent parser of the rule expressions:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | compare
+//	compare = primary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) primary ]
+//	primary = literal | path | ident "(" or ")" | "(" or ")"
+type parser struct {
+	src    string
+	tokens []token
+	cur    int
+}
+
+func (p *parser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokenRParen, ")", i})
+			i++
+		case c == ';':
+			p.tokens = append(p.tokens, token{tokenSemicolon, ";", i})
+			i++
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return errors.Errorf("unterminated string at position %d", i)
+			}
+			text, err := strconv.Unquote(`"` + strings.ReplaceAll(s[i+1:j], `"`, `\"`) + `"`)
+			if err != nil {
+				return errors.Errorf("invalid string at position %d", i)
+			}
+			p.tokens = append(p.tokens, token{tokenString, text, i})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i + 1
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokenNumber, s[i:j], i})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] == '.' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokenIdent, s[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!"} {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return errors.Errorf("unexpected %q at position %d", c, i)
+			}
+			p.tokens = append(p.tokens, token{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, token{tokenEOF, "", len(s)})
+
+	return nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.cur] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.cur]
+	if t.kind != tokenEOF {
+		p.cur++
+	}
+
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOperator && p.peek().text == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenOperator && p.peek().text == "!" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); {
+	case t.kind == tokenOperator && t.text != "||" && t.text != "&&" && t.text != "!":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: t.text, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return literalNode{value: f}, nil
+	case tokenString:
+		return literalNode{value: t.text}, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokenRParen {
+			return nil, errors.Errorf("expected ) at position %d", r.pos)
+		}
+		return n, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			if t.text != "has" && t.text != "len" {
+				return nil, errors.Errorf("unknown function %q at position %d", t.text, t.pos)
+			}
+			p.next()
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if r := p.next(); r.kind != tokenRParen {
+				return nil, errors.Errorf("expected ) at position %d", r.pos)
+			}
+			return &callNode{name: t.text, arg: arg}, nil
+		}
+		return pathNode{path: strings.Split(t.text, ".")}, nil
+	case tokenEOF:
+		return nil, errors.Errorf("unexpected end of expression at position %d", t.pos)
+	}
+
+	return nil, errors.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// evalContext is the context of the evaluation of an expression.
+type evalContext struct {
+	// parent is the struct of the field the rule applies to.
+	parent reflect.Value
+	// self is the field the rule applies to.
+	self reflect.Value
+}
+
+// node is a node of the syntax tree of an expression. Values are nil, bool,
+// float64, string or, for other kinds, reflect.Value.
+type node interface {
+	eval(ctx *evalContext) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(*evalContext) (interface{}, error) { return n.value, nil }
+
+type pathNode struct{ path []string }
+
+func (n pathNode) eval(ctx *evalContext) (interface{}, error) {
+	v, err := n.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toValue(v), nil
+}
+
+// resolve returns the field at the path.
+func (n pathNode) resolve(ctx *evalContext) (reflect.Value, error) {
+	v := ctx.parent
+	path := n.path
+	if path[0] == "self" {
+		v = ctx.self
+		path = path[1:]
+	}
+
+	return resolveField(v, path)
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(ctx *evalContext) (interface{}, error) {
+	b, err := evalBool(n.operand, ctx)
+	return !b, err
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(ctx *evalContext) (interface{}, error) {
+	left, err := evalBool(n.left, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "||" && left || n.op == "&&" && !left {
+		return left, nil
+	}
+
+	return evalBool(n.right, ctx)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(ctx *evalContext) (interface{}, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compareOrdered(n.op, l, r), nil
+		}
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	return nil, errors.Errorf("cannot compare %v %s %v", left, n.op, right)
+}
+
+func compareOrdered[T float64 | string](op string, l, r T) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+func equal(left, right interface{}) bool {
+	if lv, ok := left.(reflect.Value); ok {
+		return right == nil && !isSet(lv)
+	}
+	if rv, ok := right.(reflect.Value); ok {
+		return left == nil && !isSet(rv)
+	}
+
+	return left == right
+}
+
+type callNode struct {
+	name string
+	arg  node
+}
+
+func (n *callNode) eval(ctx *evalContext) (interface{}, error) {
+	arg, err := n.arg.eval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.name == "has" {
+		if p, ok := n.arg.(pathNode); ok {
+			v, err := p.resolve(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return isSetField(v), nil
+		}
+		return isSet(arg), nil
+	}
+
+	switch a := arg.(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(len(a)), nil
+	case reflect.Value:
+		switch a.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return float64(a.Len()), nil
+		}
+	}
+
+	return nil, errors.Errorf("len: unsupported argument %v", arg)
+}
+
+func evalBool(n node, ctx *evalContext) (bool, error) {
+	v, err := n.eval(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case nil:
+		return false, nil
+	}
+
+	return isSet(v), nil
+}
+
+// resolveField returns the field of v at the path of json names.
+func resolveField(v reflect.Value, path []string) (reflect.Value, error) {
+	for _, name := range path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, errors.Errorf("cannot get %q of %s", name, v.Kind())
+		}
+
+		fld, ok := field.JSONField(v.Type(), name)
+		if !ok {
+			return reflect.Value{}, errors.Errorf("unknown field %q", name)
+		}
+		// a nil embedded struct leaves the field unset
+		var err error
+		if v, err = v.FieldByIndexErr(fld.Index); err != nil {
+			return reflect.Value{}, nil
+		}
+	}
+
+	return v, nil
+}
+
+// toValue converts v into a value of an expression.
+func toValue(v reflect.Value) interface{} {
+	ref := v
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		ref, v = v, v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+
+	// keep the pointer so that a pointer to a zero struct is set
+	if v.Kind() == reflect.Struct {
+		return ref
+	}
+
+	return v
+}
+
+// isSetField reports whether the field v is set: a non-nil pointer is set,
+// even to a zero value, e.g. an optional replicas explicitly set to 0.
+// Otherwise, its value must be set.
+func isSetField(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return !v.IsNil()
+	}
+
+	return isSet(toValue(v))
+}
+
+// isSet reports whether the value of an expression is set: not null, zero
+// or empty.
+func isSet(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case reflect.Value:
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return v.Len() > 0
+		}
+		return !v.IsZero()
+	}
+
+	return true
+}
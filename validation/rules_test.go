@@ -0,0 +1,186 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/coding-hui/common/validation/field"
+)
+
+type tlsConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CertFile string `json:"certFile" rules:"required if enabled"`
+	KeyFile  string `json:"keyFile" rules:"required if enabled"`
+}
+
+type autoscaling struct {
+	MinReplicas int  `json:"minReplicas" rules:"self <= maxReplicas"`
+	MaxReplicas int  `json:"maxReplicas" rules:"self > 0; self <= 100 if !has(burst)"`
+	Burst       bool `json:"burst"`
+}
+
+type server struct {
+	Mode        string            `json:"mode" rules:"self == 'http' || self == \"https\""`
+	TLS         *tlsConfig        `json:"tls,omitempty" rules:"required if mode == 'https'"`
+	Autoscaling []autoscaling     `json:"autoscaling"`
+	Labels      map[string]string `json:"labels" rules:"len(self) <= 2"`
+}
+
+// ruleErrors returns the errors with the origin of the rules.
+func ruleErrors(errs ...*field.Error) field.ErrorList {
+	for _, err := range errs {
+		err.WithOrigin(RulesTag)
+	}
+
+	return errs
+}
+
+func TestRules(t *testing.T) {
+	e := NewEngine()
+
+	tests := []struct {
+		data interface{}
+		want field.ErrorList
+	}{
+		{
+			&server{Mode: "http", Autoscaling: []autoscaling{{MinReplicas: 1, MaxReplicas: 2}}},
+			nil,
+		},
+		{
+			&server{Mode: "https"},
+			ruleErrors(field.Required(field.NewPath("tls"), "must be set when mode == 'https'")),
+		},
+		{
+			&server{Mode: "https", TLS: &tlsConfig{Enabled: true, CertFile: "tls.crt"}},
+			ruleErrors(field.Required(field.NewPath("tls", "keyFile"), "must be set when enabled")),
+		},
+		{
+			&server{Mode: "https", TLS: &tlsConfig{}},
+			nil,
+		},
+		{
+			&server{Mode: "ftp", Labels: map[string]string{"a": "1", "b": "2", "c": "3"}},
+			ruleErrors(
+				field.Invalid(field.NewPath("mode"), "ftp", `must satisfy self == 'http' || self == "https"`),
+				field.Invalid(field.NewPath("labels"), map[string]string{"a": "1", "b": "2", "c": "3"}, "must satisfy len(self) <= 2"),
+			),
+		},
+		{
+			&server{Mode: "http", Autoscaling: []autoscaling{
+				{MinReplicas: 1, MaxReplicas: 2},
+				{MinReplicas: 3, MaxReplicas: 2},
+				{MinReplicas: 0, MaxReplicas: 0},
+				{MinReplicas: 1, MaxReplicas: 200},
+				{MinReplicas: 1, MaxReplicas: 200, Burst: true},
+			}},
+			ruleErrors(
+				field.Invalid(field.NewPath("autoscaling").Index(1).Child("minReplicas"), 3, "must satisfy self <= maxReplicas"),
+				field.Invalid(field.NewPath("autoscaling").Index(2).Child("maxReplicas"), 0, "must satisfy self > 0"),
+				field.Invalid(field.NewPath("autoscaling").Index(3).Child("maxReplicas"), 200, "must satisfy self <= 100"),
+			),
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.want, e.Validate(test.data), "test %d", i+1)
+	}
+}
+
+func TestRulesOptionalPointers(t *testing.T) {
+	type deployment struct {
+		Replicas *int    `json:"replicas" rules:"required"`
+		Paused   *bool   `json:"paused" rules:"required"`
+		Image    *string `json:"image"`
+		Tag      string  `json:"tag" rules:"required if has(image)"`
+	}
+
+	zero, no, empty := 0, false, ""
+	e := NewEngine()
+
+	assert.Nil(t, e.Validate(&deployment{Replicas: &zero, Paused: &no}))
+	assert.Equal(t, ruleErrors(
+		field.Required(field.NewPath("replicas"), ""),
+		field.Required(field.NewPath("paused"), ""),
+	), e.Validate(&deployment{}))
+	assert.Equal(t, ruleErrors(field.Required(field.NewPath("tag"), "must be set when has(image)")),
+		e.Validate(&deployment{Replicas: &zero, Paused: &no, Image: &empty}))
+}
+
+func TestRegisterRules(t *testing.T) {
+	type window struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}
+	type schedule struct {
+		Window window `json:"window"`
+		Cron   string `json:"cron"`
+	}
+
+	e := NewEngine()
+	assert.NoError(t, e.RegisterRules(window{}, Rule{Field: "end", Expr: "end > start", Message: "must be after start"}))
+	assert.NoError(t, e.RegisterRules(&schedule{}, Rule{Field: "cron", Required: true, When: "window.end == 0"}))
+	assert.Error(t, e.RegisterRules(window{}, Rule{Field: "end", Expr: "end >"}))
+	assert.Error(t, e.RegisterRules(window{}, Rule{Field: "end"}))
+	assert.Error(t, e.RegisterRules(1, Rule{Expr: "true"}))
+
+	errs := e.Validate(&schedule{Window: window{Start: 2, End: 1}})
+	assert.Equal(t, ruleErrors(field.Invalid(field.NewPath("window", "end"), 1, "must be after start")), errs)
+
+	errs = e.Validate(&schedule{})
+	assert.Equal(t, ruleErrors(
+		field.Required(field.NewPath("cron"), "must be set when window.end == 0"),
+		field.Invalid(field.NewPath("window", "end"), 0, "must be after start"),
+	), errs)
+
+	errs = e.ValidatePartial(&schedule{}, []string{"cron"})
+	assert.Equal(t, ruleErrors(field.Required(field.NewPath("cron"), "must be set when window.end == 0")), errs)
+}
+
+func TestRulesInvalid(t *testing.T) {
+	type invalid struct {
+		A int `json:"a" rules:"self < unknown"`
+		B int `json:"b" rules:"self <"`
+		C int `json:"c" rules:"self < 'text'"`
+	}
+
+	errs := NewEngine().Validate(&invalid{})
+	if assert.Len(t, errs, 3) {
+		for _, err := range errs {
+			assert.Equal(t, field.ErrorTypeInternal, err.Type, err.Error())
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    []Rule
+		wantErr bool
+	}{
+		{"required", []Rule{{Required: true}}, false},
+		{"required if a.b && !c", []Rule{{Required: true, When: "a.b && !c"}}, false},
+		{"self > 0; self < 10 if has(x)", []Rule{{Expr: "self > 0"}, {Expr: "self < 10", When: "has(x)"}}, false},
+		{"(a || b) && c == 'x;y'", []Rule{{Expr: "(a || b) && c == 'x;y'"}}, false},
+		{"self >", nil, true},
+		{"self > 0 0", nil, true},
+		{"size(self) > 0", nil, true},
+		{"self == 'x", nil, true},
+		{"self # 1", nil, true},
+	}
+
+	for i, test := range tests {
+		rules, err := parseRules(test.tag)
+		if test.wantErr {
+			assert.Error(t, err, "test %d", i+1)
+			continue
+		}
+		if assert.NoError(t, err, "test %d", i+1) {
+			assert.Equal(t, test.want, rules, "test %d", i+1)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the $schema of the documents returned by
+// Engine.JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema (draft 2020-12). It's also the schema object of
+// OpenAPI 3.1, so it can be used as is in the components of an API document.
+type Schema struct {
+	Schema string `json:"$schema,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Format string `json:"format,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	Enum []interface{} `json:"enum,omitempty"`
+}
+
+// SchemaFunc describes in s the constraints of a tag of the validator with
+// the given parameter, e.g. sets s.MaxLength for max=10 on a string. The type
+// of s is already set.
+type SchemaFunc func(s *Schema, param string)
+
+// RegisterSchema registers how a tag, usually a custom one, is described in
+// the schemas of e. It must not be called concurrently with Schema.
+func (e *Engine) RegisterSchema(tag string, fn SchemaFunc) {
+	if e.schemas == nil {
+		e.schemas = map[string]SchemaFunc{}
+	}
+	e.schemas[tag] = fn
+}
+
+// Schema returns the schema of the type of v, derived from the types, the
+// `json` tags and the `validate` tags of its fields. Fields tagged required,
+// or with an unconditional required rule (see RulesTag), are required.
+//
+// Tags without SchemaFunc, alternatives such as "ipv4|ipv6" and the rules
+// other than required are not described. A recursive reference to a struct
+// is described as an object without properties.
+func (e *Engine) Schema(v interface{}) *Schema {
+	g := &schemaGenerator{engine: e, seen: map[reflect.Type]bool{}}
+
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+// JSONSchema returns the JSON Schema document of the type of v.
+func (e *Engine) JSONSchema(v interface{}) ([]byte, error) {
+	s := e.Schema(v)
+	s.Schema = JSONSchemaDialect
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaGenerator generates the schema of a type.
+type schemaGenerator struct {
+	engine *Engine
+	seen   map[reflect.Type]bool
+}
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) *Schema {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == nil:
+		return &Schema{}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case implements(t, jsonMarshalerType):
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		s := &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+		if t.Kind() == reflect.Array {
+			n := t.Len()
+			s.MinItems, s.MaxItems = &n, &n
+		}
+		return s
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object"}
+		if g.seen[t] {
+			return s
+		}
+		g.seen[t] = true
+		defer delete(g.seen, t)
+
+		s.Properties = map[string]*Schema{}
+		g.addProperties(s, t)
+		return s
+	}
+
+	return &Schema{}
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// addProperties adds the fields of the struct type t to s, including the
+// fields of its embedded structs.
+func (g *schemaGenerator) addProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		fld := t.Field(i)
+		if fld.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := jsonTagName(fld)
+		if fld.Anonymous && name == "" {
+			typ := fld.Type
+			if typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+			}
+			if typ.Kind() == reflect.Struct {
+				g.addProperties(s, typ)
+				continue
+			}
+		}
+		if fld.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = fld.Name
+		}
+
+		prop := g.schemaOf(fld.Type)
+		required := g.apply(prop, g.tags(fld.Tag.Get("validate")))
+		if rules, err := parseRules(fld.Tag.Get(RulesTag)); err == nil {
+			for _, r := range rules {
+				required = required || r.Required && r.When == ""
+			}
+		}
+		if required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// tags splits a validate tag, expanding the aliases of the engine.
+func (g *schemaGenerator) tags(tag string) []string {
+	tags := []string{}
+	for _, t := range strings.Split(tag, ",") {
+		if alias, ok := g.engine.aliases[t]; ok {
+			tags = append(tags, strings.Split(alias, ",")...)
+		} else if t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return tags
+}
+
+// apply describes the tags in s and reports whether they require the field.
+func (g *schemaGenerator) apply(s *Schema, tags []string) bool {
+	required := false
+	for i, tag := range tags {
+		if tag == "dive" {
+			g.dive(s, tags[i+1:])
+			break
+		}
+		if strings.Contains(tag, "|") {
+			continue
+		}
+
+		name, param := tag, ""
+		if j := strings.Index(tag, "="); j >= 0 {
+			name, param = tag[:j], tag[j+1:]
+			param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+		}
+		if name == "required" {
+			required = true
+		}
+
+		fn, ok := g.engine.schemas[name]
+		if !ok {
+			fn = schemaFuncs[name]
+		}
+		if fn != nil {
+			fn(s, param)
+		}
+	}
+
+	return required
+}
+
+// dive describes the tags following dive in the schemas of the keys and of
+// the elements of s.
+func (g *schemaGenerator) dive(s *Schema, tags []string) {
+	elem := s.Items
+	if s.Type == "object" {
+		elem = s.AdditionalProperties
+	}
+	if elem == nil {
+		return
+	}
+
+	if len(tags) > 0 && tags[0] == "keys" {
+		end := len(tags)
+		for i, tag := range tags {
+			if tag == "endkeys" {
+				end = i
+				break
+			}
+		}
+
+		keys := &Schema{Type: "string"}
+		g.apply(keys, tags[1:end])
+		if !reflect.DeepEqual(keys, &Schema{Type: "string"}) {
+			s.PropertyNames = keys
+		}
+
+		tags = tags[end:]
+		if len(tags) > 0 {
+			tags = tags[1:]
+		}
+	}
+
+	g.apply(elem, tags)
+}
+
+// schemaFuncs describe the built-in tags of the validator, and the tags of
+// the package.
+var schemaFuncs = map[string]SchemaFunc{
+	"required": func(s *Schema, _ string) {
+		if s.Type == "string" && s.MinLength == nil {
+			one := 1
+			s.MinLength = &one
+		}
+	},
+	"len": func(s *Schema, param string) {
+		bound(s, param, true, false)
+		bound(s, param, false, false)
+	},
+	"min":      func(s *Schema, param string) { bound(s, param, true, false) },
+	"gte":      func(s *Schema, param string) { bound(s, param, true, false) },
+	"gt":       func(s *Schema, param string) { bound(s, param, true, true) },
+	"max":      func(s *Schema, param string) { bound(s, param, false, false) },
+	"lte":      func(s *Schema, param string) { bound(s, param, false, false) },
+	"lt":       func(s *Schema, param string) { bound(s, param, false, true) },
+	"eq":       func(s *Schema, param string) { s.Enum = []interface{}{enumValue(s, param)} },
+	"oneof":    oneOfSchema,
+	"unique":   func(s *Schema, _ string) { s.UniqueItems = s.Type == "array" },
+	"email":    formatSchema("email"),
+	"url":      formatSchema("uri"),
+	"uri":      formatSchema("uri"),
+	"uuid":     formatSchema("uuid"),
+	"uuid4":    formatSchema("uuid"),
+	"ipv4":     formatSchema("ipv4"),
+	"ip4_addr": formatSchema("ipv4"),
+	"ipv6":     formatSchema("ipv6"),
+	"ip6_addr": formatSchema("ipv6"),
+	"hostname": formatSchema("hostname"),
+	"alpha":    patternSchema("^[a-zA-Z]+$"),
+	"alphanum": patternSchema("^[a-zA-Z0-9]+$"),
+	"numeric":  patternSchema(`^[-+]?[0-9]+(?:\.[0-9]+)?$`),
+	"name":     nameSchema,
+	"description": func(s *Schema, _ string) {
+		n := maxDescriptionLength
+		s.MaxLength = &n
+	},
+}
+
+// nameSchema describes the qualified names, see IsQualifiedName. The length
+// of the name part is bounded by the pattern, but the length of the prefix is
+// only bounded by the maximum length of the whole name.
+func nameSchema(s *Schema, _ string) {
+	s.Pattern = "^(" + dns1123SubdomainFmt + "/)?" + boundedQualifiedNameFmt + "$"
+	n := DNS1123SubdomainMaxLength + len("/") + qualifiedNameMaxLength
+	s.MaxLength = &n
+}
+
+// boundedQualifiedNameFmt is qualifiedNameFmt with its maximum length.
+var boundedQualifiedNameFmt = "(" + qnameCharFmt + qnameExtCharFmt +
+	"{0," + strconv.Itoa(qualifiedNameMaxLength-2) + "})?" + qnameCharFmt
+
+func formatSchema(format string) SchemaFunc {
+	return func(s *Schema, _ string) {
+		s.Format = format
+	}
+}
+
+func patternSchema(pattern string) SchemaFunc {
+	return func(s *Schema, _ string) {
+		s.Pattern = pattern
+	}
+}
+
+// bound sets the lower or the upper bound of the value, the length, or the
+// number of items or properties of s.
+func bound(s *Schema, param string, lower, exclusive bool) {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	n := int(f)
+	switch {
+	case exclusive && lower:
+		n++
+	case exclusive:
+		n--
+	}
+
+	switch s.Type {
+	case "string":
+		setBound(&s.MinLength, &s.MaxLength, n, lower)
+	case "array":
+		setBound(&s.MinItems, &s.MaxItems, n, lower)
+	case "object":
+		setBound(&s.MinProperties, &s.MaxProperties, n, lower)
+	case "integer", "number":
+		switch {
+		case exclusive && lower:
+			s.ExclusiveMinimum = &f
+		case exclusive:
+			s.ExclusiveMaximum = &f
+		case lower:
+			s.Minimum = &f
+		default:
+			s.Maximum = &f
+		}
+	}
+}
+
+func setBound(lo, hi **int, n int, lower bool) {
+	if lower {
+		*lo = &n
+	} else {
+		*hi = &n
+	}
+}
+
+// oneOfValues matches the values of a oneof tag, which can be quoted.
+var oneOfValues = regexp.MustCompile(`'[^']*'|\S+`)
+
+func oneOfSchema(s *Schema, param string) {
+	s.Enum = []interface{}{}
+	for _, value := range oneOfValues.FindAllString(param, -1) {
+		s.Enum = append(s.Enum, enumValue(s, strings.Trim(value, "'")))
+	}
+}
+
+// enumValue converts a parameter to a value of the type of s.
+func enumValue(s *Schema, param string) interface{} {
+	if s.Type == "integer" || s.Type == "number" {
+		if f, err := strconv.ParseFloat(param, 64); err == nil {
+			return f
+		}
+	}
+
+	return param
+}
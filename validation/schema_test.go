@@ -0,0 +1,138 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "github.com/coding-hui/common/meta/v1"
+)
+
+type schemaMeta struct {
+	Name        string    `json:"name" validate:"required,name"`
+	Description string    `json:"description,omitempty" validate:"description"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+}
+
+type schemaNode struct {
+	Value    uint8         `json:"value" validate:"max=9"`
+	Children []*schemaNode `json:"children,omitempty"`
+}
+
+type schemaApp struct {
+	schemaMeta `json:",inline"`
+
+	Kind     string            `json:"kind" validate:"oneof=web 'cron job'"`
+	Replicas int32             `json:"replicas" validate:"gte=1,lt=10"`
+	Ratio    float64           `json:"ratio" validate:"gt=0,lte=1"`
+	Owner    string            `json:"owner" rules:"required"`
+	Admin    string            `json:"admin" rules:"required if kind == 'web'"`
+	Email    string            `json:"email,omitempty" validate:"omitempty,email"`
+	Image    string            `json:"image" validate:"image"`
+	Tags     []string          `json:"tags" validate:"min=1,max=5,unique,dive,required,max=10"`
+	Labels   map[string]string `json:"labels" validate:"dive,keys,alphanum,endkeys,required"`
+	Ports    [2]int            `json:"ports"`
+	Address  string            `json:"address" validate:"ipv4|ipv6"`
+	Data     []byte            `json:"data"`
+	Root     schemaNode        `json:"root"`
+	Secret   string            `json:"-" validate:"required"`
+	internal string
+}
+
+func TestSchema(t *testing.T) {
+	e := NewEngine()
+	e.RegisterAlias("image", "required,contains=:")
+	e.RegisterSchema("contains", func(s *Schema, param string) {
+		s.Pattern = regexp.QuoteMeta(param)
+	})
+
+	data, err := e.JSONSchema(&schemaApp{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 317, "pattern": "^(`+
+		jsonString(dns1123SubdomainFmt)+`/)?`+jsonString(boundedQualifiedNameFmt)+`$"},
+			"description": {"type": "string", "maxLength": 255},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"kind": {"type": "string", "enum": ["web", "cron job"]},
+			"replicas": {"type": "integer", "format": "int32", "minimum": 1, "exclusiveMaximum": 10},
+			"ratio": {"type": "number", "format": "double", "exclusiveMinimum": 0, "maximum": 1},
+			"owner": {"type": "string"},
+			"admin": {"type": "string"},
+			"email": {"type": "string", "format": "email"},
+			"image": {"type": "string", "minLength": 1, "pattern": ":"},
+			"tags": {
+				"type": "array",
+				"minItems": 1,
+				"maxItems": 5,
+				"uniqueItems": true,
+				"items": {"type": "string", "minLength": 1, "maxLength": 10}
+			},
+			"labels": {
+				"type": "object",
+				"propertyNames": {"type": "string", "pattern": "^[a-zA-Z0-9]+$"},
+				"additionalProperties": {"type": "string", "minLength": 1}
+			},
+			"ports": {
+				"type": "array",
+				"minItems": 2,
+				"maxItems": 2,
+				"items": {"type": "integer", "format": "int64"}
+			},
+			"address": {"type": "string"},
+			"data": {"type": "string", "format": "byte"},
+			"root": {
+				"type": "object",
+				"properties": {
+					"value": {"type": "integer", "minimum": 0, "maximum": 9},
+					"children": {"type": "array", "items": {"type": "object"}}
+				}
+			}
+		},
+		"required": ["name", "owner", "image"]
+	}`, string(data))
+}
+
+func TestSchemaObjectMeta(t *testing.T) {
+	s := NewEngine().Schema(metav1.ObjectMeta{})
+
+	assert.NotContains(t, s.Properties, "ID")
+	assert.NotContains(t, s.Properties, "ExtendShadow")
+	assert.Equal(t, "object", s.Properties["extend"].Type)
+	assert.Equal(t, &Schema{}, s.Properties["extend"].AdditionalProperties)
+
+	name := s.Properties["name"]
+	if assert.NotNil(t, name) {
+		pattern := regexp.MustCompile(name.Pattern)
+		for _, value := range []string{"nginx", "my.name", "example.com/MyName", strings.Repeat("a", 63)} {
+			assert.True(t, pattern.MatchString(value), value)
+			assert.Empty(t, IsQualifiedName(value), value)
+		}
+		for _, value := range []string{"-nginx", "a/b/c", "Example.com/name", "name_", strings.Repeat("a", 64)} {
+			assert.False(t, pattern.MatchString(value), value)
+			assert.NotEmpty(t, IsQualifiedName(value), value)
+		}
+		if assert.NotNil(t, name.MaxLength) {
+			long := strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." +
+				strings.Repeat("d", 63) + ".e/" + strings.Repeat("f", 63)
+			assert.Greater(t, len(long), *name.MaxLength)
+			assert.NotEmpty(t, IsQualifiedName(long))
+		}
+	}
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+
+	return string(data[1 : len(data)-1])
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 coding-hui. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store, meant for tests. It's safe for
+// concurrent use. The zero value is an empty store.
+type MemoryStore struct {
+	// Delay delays every lookup, e.g. to test timeouts. The lookups return
+	// the error of the context if it's done before.
+	Delay time.Duration
+
+	mu      sync.RWMutex
+	objects map[string]map[string]string // kind -> key -> id
+}
+
+var _ Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: map[string]map[string]string{}}
+}
+
+// Add adds an object of the kind with the given key and ID.
+func (s *MemoryStore) Add(kind, key, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the zero value is ready to use
+	if s.objects == nil {
+		s.objects = map[string]map[string]string{}
+	}
+	if s.objects[kind] == nil {
+		s.objects[kind] = map[string]string{}
+	}
+	s.objects[kind][key] = id
+}
+
+// Remove removes the object of the kind with the given key.
+func (s *MemoryStore) Remove(kind, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.objects[kind], key)
+}
+
+// Lookup implements Store.
+func (s *MemoryStore) Lookup(ctx context.Context, kind, key string) (string, bool, error) {
+	if s.Delay > 0 {
+		timer := time.NewTimer(s.Delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, found := s.objects[kind][key]
+
+	return id, found, nil
+}
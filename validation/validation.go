@@ -5,14 +5,11 @@
 package validation
 
 import (
-	"fmt"
 	"os"
 	"reflect"
 
-	english "github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	"github.com/go-playground/validator/v10/translations/en"
 
 	"github.com/coding-hui/common/validation/field"
 )
@@ -22,64 +19,19 @@ const (
 )
 
 // Validator is a custom validator for configs.
+//
+// Each Validator creates its own validation engine: use an Engine to validate
+// many values with the same configuration.
 type Validator struct {
-	val   *validator.Validate
-	data  interface{}
-	trans ut.Translator
+	engine *Engine
+	data   interface{}
 }
 
 // NewValidator creates a new Validator.
 func NewValidator(data interface{}) *Validator {
-	result := validator.New()
-
-	// independent validators
-	result.RegisterValidation("dir", validateDir)                 // nolint: errcheck // no need
-	result.RegisterValidation("file", validateFile)               // nolint: errcheck // no need
-	result.RegisterValidation("description", validateDescription) // nolint: errcheck // no need
-	result.RegisterValidation("name", validateName)               // nolint: errcheck // no need
-
-	// default translations
-	eng := english.New()
-	uni := ut.New(eng, eng)
-	trans, _ := uni.GetTranslator("en")
-	err := en.RegisterDefaultTranslations(result, trans)
-	if err != nil {
-		panic(err)
-	}
-
-	// additional translations
-	translations := []struct {
-		tag         string
-		translation string
-	}{
-		{
-			tag:         "dir",
-			translation: "{0} must point to an existing directory, but found '{1}'",
-		},
-		{
-			tag:         "file",
-			translation: "{0} must point to an existing file, but found '{1}'",
-		},
-		{
-			tag:         "description",
-			translation: fmt.Sprintf("must be less than %d", maxDescriptionLength),
-		},
-		{
-			tag:         "name",
-			translation: "is not a invalid name",
-		},
-	}
-	for _, t := range translations {
-		err = result.RegisterTranslation(t.tag, trans, registrationFunc(t.tag, t.translation), translateFunc)
-		if err != nil {
-			panic(err)
-		}
-	}
-
 	return &Validator{
-		val:   result,
-		data:  data,
-		trans: trans,
+		engine: newEngine(false),
+		data:   data,
 	}
 }
 
@@ -105,26 +57,15 @@ func translateFunc(ut ut.Translator, fe validator.FieldError) string {
 // Validate validates config for errors and returns an error (it can be casted to
 // ValidationErrors, containing a list of errors inside). When error is printed as string, it will
 // automatically contains the full list of validation errors.
+// The messages of the errors are in the DefaultLocale. Unlike ValidateLocale,
+// they are reported as the bad values of the errors, without detail.
 func (v *Validator) Validate() field.ErrorList {
-	// validate policy
-	err := v.val.Struct(v.data)
-	if err == nil {
-		return nil
-	}
-
-	// this check is only needed when your code could produce
-	// an invalid value for validation such as interface with nil
-	// value most including myself do not usually have code like this.
-	if _, ok := err.(*validator.InvalidValidationError); ok {
-		return field.ErrorList{field.Invalid(field.NewPath(""), err.Error(), "")}
-	}
-
-	allErrs := field.ErrorList{}
-
-	// collect human-readable errors
-	vErrors, _ := err.(validator.ValidationErrors)
-	for _, vErr := range vErrors {
-		allErrs = append(allErrs, field.Invalid(field.NewPath(vErr.Namespace()), vErr.Translate(v.trans), ""))
+	allErrs := v.engine.Validate(v.data)
+	for _, err := range allErrs {
+		// the errors of the validator have the tag as origin
+		if err.Origin != "" {
+			err.BadValue, err.Detail = err.Detail, ""
+		}
 	}
 
 	return allErrs
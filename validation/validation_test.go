@@ -160,3 +160,13 @@ func TestConfigValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateErrorShape(t *testing.T) {
+	val := NewValidator(&testStruct{Host: "0.0.0.0", Port: 80})
+	errs := val.Validate()
+	if !assert.Len(t, errs, 1) {
+		return
+	}
+	assert.Equal(t, `testStruct.SomeDir: Invalid value: "SomeDir is a required field"`, errs[0].Error())
+	assert.Equal(t, "", errs[0].Detail)
+}